@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CAATagIssue authorizes a certificate authority to issue certificates
+	CAATagIssue = "issue"
+	// CAATagIssueWild authorizes a certificate authority to issue wildcard
+	// certificates
+	CAATagIssueWild = "issuewild"
+	// CAATagIODEF specifies where certificate authorities report policy
+	// violations
+	CAATagIODEF = "iodef"
+
+	maxCAAFlags = 255
+)
+
+// CAAData is the parsed form of CAA record data (RFC 8659)
+type CAAData struct {
+	Flags int
+	Tag   string
+	Value string
+}
+
+// ParseCAA parses and validates CAA record data in its presentation format:
+// <flags> <tag> <value>, where the value may be quoted
+func ParseCAA(data string) (*CAAData, error) {
+	fields := strings.SplitN(strings.TrimSpace(data), " ", 3)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("CAA data must be of the form <flags> <tag> <value>: %q", data)
+	}
+
+	flags, err := strconv.Atoi(fields[0])
+	if err != nil || flags < 0 || flags > maxCAAFlags {
+		return nil, fmt.Errorf("CAA flags must be between 0..%d: %q", maxCAAFlags, fields[0])
+	}
+
+	value, err := unquoteCAA(strings.TrimSpace(fields[2]))
+	if err != nil {
+		return nil, err
+	}
+
+	caa := &CAAData{Flags: flags, Tag: strings.ToLower(fields[1]), Value: value}
+	if err := caa.validate(); err != nil {
+		return nil, err
+	}
+	return caa, nil
+}
+
+// String renders the data in its canonical presentation format, with a
+// quoted value
+func (c *CAAData) String() string {
+	return fmt.Sprintf("%d %s %s", c.Flags, c.Tag, strconv.Quote(c.Value))
+}
+
+func (c *CAAData) validate() error {
+	switch c.Tag {
+	case CAATagIssue, CAATagIssueWild:
+		return validateCAAIssuer(c.Value)
+	case CAATagIODEF:
+		u, err := url.Parse(c.Value)
+		if err != nil || (u.Scheme != "mailto" && u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("CAA iodef value must be a mailto:, http: or https: URL: %q", c.Value)
+		}
+		return nil
+	}
+	return fmt.Errorf("CAA tag must be one of %s, %s or %s: %q", CAATagIssue, CAATagIssueWild, CAATagIODEF, c.Tag)
+}
+
+// validateCAAIssuer checks an issue or issuewild value: an optional issuer
+// domain name followed by semicolon separated key=value parameters. A value
+// of ";" forbids issuance.
+func validateCAAIssuer(value string) error {
+	parts := strings.Split(value, ";")
+	if issuer := strings.TrimSpace(parts[0]); issuer != "" {
+		if err := ValidateHostname(issuer); err != nil {
+			return fmt.Errorf("CAA issuer: %w", err)
+		}
+	} else if len(parts) == 1 {
+		return errors.New(`CAA issue value must name an issuer, or be ";" to forbid issuance`)
+	}
+
+	for _, param := range parts[1:] {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || !isAlphanumeric(kv[0]) {
+			return fmt.Errorf("CAA parameters must be of the form key=value: %q", param)
+		}
+	}
+	return nil
+}
+
+func unquoteCAA(value string) (string, error) {
+	if !strings.HasPrefix(value, `"`) {
+		if value == "" || strings.ContainsAny(value, " \t") {
+			return "", fmt.Errorf("CAA value must be quoted if empty or containing spaces: %q", value)
+		}
+		return value, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return "", fmt.Errorf("CAA value is not a valid quoted string: %s", value)
+	}
+	return unquoted, nil
+}
@@ -0,0 +1,58 @@
+package api
+
+import "testing"
+
+func TestParseCAA(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Data     string
+		Expected string
+		Negative bool
+	}{
+		{"Given an issue property", `0 issue "letsencrypt.org"`, `0 issue "letsencrypt.org"`, false},
+		{"Given an unquoted value", `0 issue letsencrypt.org`, `0 issue "letsencrypt.org"`, false},
+		{"Given an uppercase tag", `128 ISSUEWILD "letsencrypt.org"`, `128 issuewild "letsencrypt.org"`, false},
+		{"Given issuer parameters", `0 issue "ca.example.net; account=230123"`, `0 issue "ca.example.net; account=230123"`, false},
+		{"Given forbidden issuance", `0 issuewild ";"`, `0 issuewild ";"`, false},
+		{"Given an iodef mailto URL", `0 iodef "mailto:security@example.com"`, `0 iodef "mailto:security@example.com"`, false},
+		{"Given an iodef https URL", `0 iodef "https://iodef.example.com/"`, `0 iodef "https://iodef.example.com/"`, false},
+		{"Given missing fields", `0 issue`, "", true},
+		{"Given non-numeric flags", `critical issue "letsencrypt.org"`, "", true},
+		{"Given out of range flags", `256 issue "letsencrypt.org"`, "", true},
+		{"Given an unknown tag", `0 contactemail "security@example.com"`, "", true},
+		{"Given an invalid issuer", `0 issue "not a domain"`, "", true},
+		{"Given an empty issuer", `0 issue ""`, "", true},
+		{"Given an invalid parameter", `0 issue "letsencrypt.org; account"`, "", true},
+		{"Given an iodef without a scheme", `0 iodef "security@example.com"`, "", true},
+		{"Given an unterminated value", `0 issue "letsencrypt.org`, "", true},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			caa, err := ParseCAA(test.Data)
+			if test.Negative {
+				if err == nil {
+					t.Errorf("expected %q to be invalid", test.Data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse %q: %s", test.Data, err)
+			}
+			if got := caa.String(); got != test.Expected {
+				t.Errorf("expected %q, got %q", test.Expected, got)
+			}
+		})
+	}
+}
+
+func TestEquivalentData(t *testing.T) {
+	if !EquivalentData(CAAType, `0 issue letsencrypt.org`, `0 issue "letsencrypt.org"`) {
+		t.Error("expected CAA data to ignore quoting")
+	}
+	if EquivalentData(CAAType, `0 issue "letsencrypt.org"`, `0 issuewild "letsencrypt.org"`) {
+		t.Error("expected CAA tags to be compared")
+	}
+	if EquivalentData(AType, `127.0.0.1`, `"127.0.0.1"`) {
+		t.Error("expected A data to be compared literally")
+	}
+}
@@ -0,0 +1,317 @@
+// Package cassette provides an http.RoundTripper that records GoDaddy API
+// interactions to a file and replays them deterministically in tests.
+//
+// A Recorder plugs into the api client as its transport:
+//
+//	rec, err := cassette.New("testdata/records.json", cassette.ModeReplay)
+//	client, err := api.NewClient(baseURL, key, secret,
+//		api.WithTransport(rec), api.WithRateLimiter(nil))
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+// Mode determines whether a Recorder captures or replays interactions
+type Mode int
+
+const (
+	// ModeRecord forwards requests to the delegate transport and captures them
+	ModeRecord Mode = iota
+	// ModeReplay serves responses from a previously recorded cassette
+	ModeReplay
+)
+
+const redacted = "REDACTED"
+
+// ErrUnmatched is returned when a replayed request has no recorded interaction
+var ErrUnmatched = errors.New("cassette: no recorded interaction matches request")
+
+// Request is the recorded form of an http.Request
+type Request struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Query   string      `json:"query,omitempty"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is the recorded form of an http.Response
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Interaction is a single request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the on-disk collection of interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Scrubber removes sensitive data from an interaction before it is saved
+type Scrubber func(*Interaction)
+
+// Opt provides support for setting optional Recorder parameters
+type Opt func(*Recorder)
+
+// WithTransport sets the transport used to reach the API while recording.
+// It defaults to http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Opt {
+	return func(r *Recorder) {
+		r.delegate = rt
+	}
+}
+
+// WithScrubber adds a scrubber that runs after the default scrubbers
+func WithScrubber(s Scrubber) Opt {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, s)
+	}
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions
+type Recorder struct {
+	path      string
+	mode      Mode
+	delegate  http.RoundTripper
+	scrubbers []Scrubber
+	cassette  *Cassette
+	played    []bool
+	sync.Mutex
+}
+
+// New constructs a Recorder for the cassette at path. In ModeReplay the
+// cassette must already exist; in ModeRecord it is overwritten by Stop.
+func New(path string, mode Mode, opts ...Opt) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		delegate:  http.DefaultTransport,
+		scrubbers: []Scrubber{ScrubHeaders, ScrubBodies},
+		cassette:  &Cassette{Interactions: make([]*Interaction, 0)},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeRecord:
+		return r, nil
+	case ModeReplay:
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: %w", err)
+		}
+		if err := json.Unmarshal(b, r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette: invalid cassette %s: %w", path, err)
+		}
+		r.played = make([]bool, len(r.cassette.Interactions))
+		return r, nil
+	}
+	return nil, fmt.Errorf("cassette: unsupported mode %d", mode)
+}
+
+// RoundTrip records or replays a single request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Query:   req.URL.Query().Encode(),
+		Headers: req.Header.Clone(),
+		Body:    string(body),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.delegate.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{
+		Request: recorded,
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: resp.Header.Clone(),
+			Body:    string(body),
+		},
+	}
+	for _, scrub := range r.scrubbers {
+		scrub(interaction)
+	}
+
+	r.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.played[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+
+		r.played[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s body=%q", ErrUnmatched, recorded.Method, req.URL.RequestURI(), recorded.Body)
+}
+
+// Unplayed returns the recorded interactions that have not been replayed
+func (r *Recorder) Unplayed() []*Interaction {
+	r.Lock()
+	defer r.Unlock()
+
+	var unplayed []*Interaction
+	for i, played := range r.played {
+		if !played {
+			unplayed = append(unplayed, r.cassette.Interactions[i])
+		}
+	}
+	return unplayed
+}
+
+// Stop saves the cassette when recording. When replaying, it returns an
+// error if any recorded interactions were not replayed.
+func (r *Recorder) Stop() error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.mode == ModeReplay {
+		for i, played := range r.played {
+			if !played {
+				req := r.cassette.Interactions[i].Request
+				return fmt.Errorf("cassette: recorded interaction was not replayed: %s %s", req.Method, req.Path)
+			}
+		}
+		return nil
+	}
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+// ScrubHeaders redacts credentials and drops cookies
+func ScrubHeaders(i *Interaction) {
+	if i.Request.Headers.Get("Authorization") != "" {
+		i.Request.Headers.Set("Authorization", redacted)
+	}
+	i.Request.Headers.Del("Cookie")
+	i.Response.Headers.Del("Set-Cookie")
+}
+
+// ScrubBodies redacts contact details and secrets from JSON bodies
+func ScrubBodies(i *Interaction) {
+	if i.Request.Body != "" {
+		i.Request.Body = api.RedactBody([]byte(i.Request.Body))
+	}
+	if i.Response.Body != "" {
+		i.Response.Body = api.RedactBody([]byte(i.Response.Body))
+	}
+}
+
+// matches compares requests by method, path, query and body. JSON bodies are
+// compared semantically.
+func matches(recorded, actual Request) bool {
+	if recorded.Method != actual.Method || recorded.Path != actual.Path {
+		return false
+	}
+
+	rq, err := url.ParseQuery(recorded.Query)
+	if err != nil || rq.Encode() != actual.Query {
+		return false
+	}
+	return equalBodies(recorded.Body, actual.Body)
+}
+
+func equalBodies(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	var av, bv interface{}
+	if json.Unmarshal([]byte(a), &av) != nil || json.Unmarshal([]byte(b), &bv) != nil {
+		return false
+	}
+	ab, _ := json.Marshal(av)
+	bb, _ := json.Marshal(bv)
+	return bytes.Equal(ab, bb)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,74 @@
+package cassette
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/n3integration/terraform-provider-godaddy/api/godaddytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := godaddytest.NewServer()
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.AType, Name: api.Ptr, Data: "127.0.0.1", TTL: 600},
+	)
+
+	rec, err := New(path, ModeRecord)
+	assert.Nil(t, err)
+	client, err := server.Client(api.WithTransport(rec))
+	assert.Nil(t, err)
+
+	domain, err := client.GetDomain("", "example.com")
+	assert.Nil(t, err)
+	records, err := client.GetDomainRecords("", "example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, rec.Stop())
+	server.Close()
+
+	b, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	for _, leaked := range []string{godaddytest.Secret, "jane.doe@example.com"} {
+		assert.False(t, strings.Contains(string(b), leaked), "expected %q to be scrubbed", leaked)
+	}
+
+	replay, err := New(path, ModeReplay)
+	assert.Nil(t, err)
+	client, err = api.NewClient("https://api.godaddy.com", "key", "secret",
+		api.WithTransport(replay), api.WithRateLimiter(nil))
+	assert.Nil(t, err)
+
+	replayedDomain, err := client.GetDomain("", "example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, domain, replayedDomain)
+
+	assert.NotNil(t, replay.Stop(), "expected unplayed interactions to be reported")
+
+	replayedRecords, err := client.GetDomainRecords("", "example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, records, replayedRecords)
+	assert.Nil(t, replay.Stop())
+
+	_, err = client.GetDomain("", "example.org")
+	assert.True(t, errors.Is(err, ErrUnmatched), "expected an unmatched error, got %v", err)
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	assert.NotNil(t, err)
+}
+
+func TestMatches(t *testing.T) {
+	recorded := Request{Method: "PUT", Path: "/v1/domains/example.com/records/A", Query: "limit=1&offset=2", Body: `[{"name":"@", "data":"127.0.0.1"}]`}
+
+	assert.True(t, matches(recorded, Request{Method: "PUT", Path: recorded.Path, Query: "limit=1&offset=2", Body: `[{"data":"127.0.0.1","name":"@"}]`}))
+	assert.False(t, matches(recorded, Request{Method: "GET", Path: recorded.Path, Query: "limit=1&offset=2", Body: recorded.Body}))
+	assert.False(t, matches(recorded, Request{Method: "PUT", Path: recorded.Path, Query: "limit=1&offset=3", Body: recorded.Body}))
+	assert.False(t, matches(recorded, Request{Method: "PUT", Path: recorded.Path, Query: "limit=1&offset=2", Body: `[]`}))
+}
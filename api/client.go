@@ -5,13 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/hashicorp/go-hclog"
 )
 
 const (
@@ -19,67 +19,124 @@ const (
 	headerAuthorization = "Authorization"
 	headerContent       = "Content-Type"
 	headerCustomerID    = "X-Shopper-Id"
+	headerUserAgent     = "User-Agent"
 	mediaTypeJSON       = "application/json"
 	rateLimit           = 1 * time.Second
+	defaultDialTimeout  = 10 * time.Second
+	defaultTimeout      = 30 * time.Second
 )
 
-// Client is a GoDaddy API client
-type Client struct {
-	baseURL string
-	key     string
-	secret  string
-	client  *http.Client
+// DNSClient provides access to a customer's domains and their records
+type DNSClient interface {
+	// GetDomains fetches the domains owned by the customer
+	GetDomains(customerID string) ([]Domain, error)
+	// GetDomain fetches the details for the provided domain
+	GetDomain(customerID, domain string) (*Domain, error)
+	// GetDomainRecords fetches all existing records for the provided domain
+	GetDomainRecords(customerID, domain string) ([]*DomainRecord, error)
+	// UpdateDomainRecords replaces all existing records of each supported type
+	UpdateDomainRecords(customerID, domain string, records []*DomainRecord) error
+	// SetDomainRecords replaces all existing records of the type and name
+	SetDomainRecords(customerID, domain, t, name string, records []*DomainRecord) error
+	// DeleteDomainRecords removes all existing records of the type and name
+	DeleteDomainRecords(customerID, domain, t, name string) error
 }
 
-// rateLimitedTransport throttles API calls to GoDaddy. It appears that
-// the rate limit is 60 requests per minute, which can be throttled and
-// enforced at a maximum of one request/second.
-type rateLimitedTransport struct {
-	delegate http.RoundTripper
-	throttle time.Time
-	sync.Mutex
-}
+var _ DNSClient = (*Client)(nil)
 
-func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	t.Lock()
-	defer t.Unlock()
+// Client is a GoDaddy API client
+type Client struct {
+	baseURL   string
+	key       string
+	secret    string
+	userAgent string
+	client    *http.Client
+	logger    hclog.Logger
+	logBodies bool
 
-	if t.throttle.After(time.Now()) {
-		delta := t.throttle.Sub(time.Now())
-		time.Sleep(delta)
-	}
+	httpClient   *http.Client
+	transport    http.RoundTripper
+	transportSet bool
+	timeout      time.Duration
+	timeoutSet   bool
+	limiter      RateLimiter
+	retry        RetryPolicy
 
-	t.throttle = time.Now().Add(rateLimit)
-	return t.delegate.RoundTrip(req)
+	snapshotDir    string
+	snapshotFormat string
 }
 
 // NewClient constructs a new GoDaddy API client or an error if the supplied
-// input is invalid.
-func NewClient(baseURL, key, secret string) (*Client, error) {
+// input is invalid. Optional parameters override the default transport,
+// timeouts, rate limiting and retry behavior.
+func NewClient(baseURL, key, secret string, opts ...ClientOpt) (*Client, error) {
 	baseURL, err := formatURL(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	var netTransport = &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: 10 * time.Second,
-		}).Dial,
-		TLSHandshakeTimeout: 10 * time.Second,
-	}
-
-	return &Client{
+	c := &Client{
 		baseURL: baseURL,
 		key:     strings.TrimSpace(key),
 		secret:  strings.TrimSpace(secret),
-		client: &http.Client{
-			Timeout: time.Second * 30,
-			Transport: &rateLimitedTransport{
-				delegate: netTransport,
-				throttle: time.Now().Add(-(rateLimit)),
-			},
+		transport: &http.Transport{
+			Dial: (&net.Dialer{
+				Timeout: defaultDialTimeout,
+			}).Dial,
+			TLSHandshakeTimeout: defaultDialTimeout,
 		},
-	}, nil
+		timeout: defaultTimeout,
+		limiter: NewRateLimiter(rateLimit),
+		logger:  NewLogger("api"),
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	c.client = c.newHTTPClient()
+	return c, nil
+}
+
+// newHTTPClient assembles the http.Client used for API calls. Requests are
+// retried according to the retry policy, and each attempt is throttled by
+// the rate limiter before reaching the underlying transport.
+func (c *Client) newHTTPClient() *http.Client {
+	hc := &http.Client{Timeout: c.timeout}
+	if c.httpClient != nil {
+		copied := *c.httpClient
+		hc = &copied
+		if c.timeoutSet {
+			hc.Timeout = c.timeout
+		}
+	}
+
+	delegate := c.transport
+	if c.httpClient != nil && !c.transportSet {
+		delegate = c.httpClient.Transport
+	}
+	if delegate == nil {
+		delegate = http.DefaultTransport
+	}
+
+	var transport http.RoundTripper = delegate
+	if c.limiter != nil {
+		transport = &rateLimitedTransport{
+			delegate: transport,
+			limiter:  c.limiter,
+		}
+	}
+	if c.retry.MaxRetries > 0 {
+		transport = &retryTransport{
+			delegate: transport,
+			policy:   c.retry,
+			logger:   c.logger,
+		}
+	}
+
+	hc.Transport = transport
+	return hc
 }
 
 func (c *Client) execute(customerID string, req *http.Request, result interface{}) error {
@@ -90,22 +147,27 @@ func (c *Client) execute(customerID string, req *http.Request, result interface{
 	req.Header.Set(headerAccept, mediaTypeJSON)
 	req.Header.Set(headerContent, mediaTypeJSON)
 	req.Header.Set(headerAuthorization, fmt.Sprintf("sso-key %s:%s", c.key, c.secret))
+	if c.userAgent != "" {
+		req.Header.Set(headerUserAgent, c.userAgent)
+	}
 
+	c.logRequest(req)
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
+		c.logger.Error("request failed", "method", req.Method, "url", req.URL.String(), "error", err)
 		return err
 	}
 
 	defer resp.Body.Close()
 
-	if err = validate(resp); err != nil {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return err
 	}
+	c.logResponse(req, resp, body, time.Since(start))
 
-	buffer := new(bytes.Buffer)
-	body, err := io.ReadAll(io.TeeReader(resp.Body, buffer))
-	log.Printf("%s %s", resp.Status, buffer)
-	if err != nil {
+	if err = validate(resp, body); err != nil {
 		return err
 	}
 
@@ -118,16 +180,47 @@ func (c *Client) execute(customerID string, req *http.Request, result interface{
 	return nil
 }
 
-func validate(resp *http.Response) error {
-	if resp.StatusCode < http.StatusBadRequest {
-		return nil
+func (c *Client) logRequest(req *http.Request) {
+	if !c.logger.IsTrace() && !c.logger.IsDebug() {
+		return
 	}
 
-	buffer := new(bytes.Buffer)
-	body, err := io.ReadAll(io.TeeReader(resp.Body, buffer))
-	log.Println(buffer)
-	if err != nil {
-		return err
+	args := []interface{}{"method", req.Method, "url", req.URL.String()}
+	if c.logger.IsTrace() {
+		args = append(args, "headers", redactHeaders(req.Header))
+	}
+	if c.logBodies && req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ := io.ReadAll(rc)
+			rc.Close()
+			args = append(args, "body", RedactBody(body))
+		}
+	}
+	c.logger.Debug("sending request", args...)
+}
+
+func (c *Client) logResponse(req *http.Request, resp *http.Response, body []byte, elapsed time.Duration) {
+	args := []interface{}{
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"bytes", len(body),
+		"elapsed", elapsed.String(),
+	}
+	if c.logBodies {
+		args = append(args, "body", RedactBody(body))
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		c.logger.Warn("request returned an error", args...)
+		return
+	}
+	c.logger.Debug("received response", args...)
+}
+
+func validate(resp *http.Response, body []byte) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
 	}
 
 	var errResp = struct {
@@ -0,0 +1,294 @@
+package api_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/n3integration/terraform-provider-godaddy/api/godaddytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDomains(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com")
+	server.AddDomain("example.org")
+	server.AddCustomerDomain("1234", "example.net")
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	domains, err := client.GetDomains("")
+	assert.Nil(t, err)
+	assert.Len(t, domains, 2)
+
+	domains, err = client.GetDomains("1234")
+	assert.Nil(t, err)
+	assert.Len(t, domains, 3)
+
+	domain, err := client.GetDomain("1234", "example.net")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.net", domain.Name)
+	assert.Equal(t, api.StatusActive, domain.Status)
+
+	_, err = client.GetDomain("", "example.net")
+	assert.NotNil(t, err)
+}
+
+func TestGetDomainRecordsPaginates(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.MaxPageSize = 2
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.AType, Name: "a", Data: "127.0.0.1", TTL: 600},
+		&api.DomainRecord{Type: api.AType, Name: "b", Data: "127.0.0.2", TTL: 600},
+		&api.DomainRecord{Type: api.AType, Name: "c", Data: "127.0.0.3", TTL: 600},
+		&api.DomainRecord{Type: api.AType, Name: "d", Data: "127.0.0.4", TTL: 600},
+		&api.DomainRecord{Type: api.AType, Name: "e", Data: "127.0.0.5", TTL: 600},
+	)
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	records, err := client.GetDomainRecords("", "example.com")
+	assert.Nil(t, err)
+	assert.Len(t, records, 5)
+	assert.Len(t, server.Requests(), 4)
+}
+
+func TestUpdateDomainRecords(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: 3600},
+		&api.DomainRecord{Type: api.TXTType, Name: api.Ptr, Data: "stale", TTL: 3600},
+	)
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	a, _ := api.NewARecord("127.0.0.1")
+	srv, _ := api.NewDomainRecord(api.Ptr, api.SRVType, "host.example.com", 3600,
+		api.Service("_ldap"), api.Protocol("_tcp"), api.Port(389))
+	assert.Nil(t, client.UpdateDomainRecords("", "example.com", []*api.DomainRecord{a, srv}))
+
+	records := server.Records("example.com")
+	types := make(map[string]int)
+	for _, rec := range records {
+		types[rec.Type]++
+	}
+	assert.Equal(t, map[string]int{api.AType: 1, api.NSType: 1, api.SRVType: 1}, types)
+}
+
+func TestSetAndDeleteDomainRecords(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.AType, Name: "www", Data: "10.0.0.1", TTL: 3600},
+		&api.DomainRecord{Type: api.AType, Name: "api", Data: "10.0.0.2", TTL: 3600},
+	)
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	a, _ := api.NewDomainRecord("www", api.AType, "10.0.0.3", 3600)
+	assert.Nil(t, client.SetDomainRecords("", "example.com", api.AType, "www", []*api.DomainRecord{a}))
+
+	records := server.Records("example.com")
+	assert.Len(t, records, 2)
+	for _, rec := range records {
+		if rec.Name == "www" {
+			assert.Equal(t, "10.0.0.3", rec.Data)
+		}
+	}
+
+	assert.Nil(t, client.DeleteDomainRecords("", "example.com", api.AType, "www"))
+	records = server.Records("example.com")
+	assert.Len(t, records, 1)
+	assert.Equal(t, "api", records[0].Name)
+}
+
+func TestUpdateDomainRecordsIDN(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("xn--bcher-kva.example")
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	cname, _ := api.NewDomainRecord("www.bücher.example.", api.CNameType, "shop.bücher.example", 3600)
+	assert.Nil(t, client.UpdateDomainRecords("", "Bücher.example", []*api.DomainRecord{cname}))
+
+	records := server.Records("xn--bcher-kva.example")
+	assert.Len(t, records, 1)
+	assert.Equal(t, "www", records[0].Name)
+	assert.Equal(t, "shop.xn--bcher-kva.example", records[0].Data)
+
+	assert.Nil(t, client.DeleteDomainRecords("", "bücher.example", api.CNameType, "www"))
+	requests := server.Requests()
+	assert.Equal(t, "/v1/domains/xn--bcher-kva.example/records/CNAME/www", requests[len(requests)-1].Path)
+}
+
+func TestUpdateDomainRecordsSnapshot(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.AType, Name: "www", Data: "10.0.0.1", TTL: 3600},
+	)
+
+	dir := t.TempDir()
+	client, err := server.Client(api.WithSnapshots(dir, api.SnapshotJSON))
+	assert.Nil(t, err)
+
+	a, _ := api.NewDomainRecord("api", api.AType, "10.0.0.2", 3600)
+	assert.Nil(t, client.UpdateDomainRecords("", "example.com", []*api.DomainRecord{a}))
+
+	snapshots, err := filepath.Glob(filepath.Join(dir, "example.com-*.json"))
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 1)
+
+	assert.Nil(t, api.RestoreSnapshot(client, "", "example.com", snapshots[0]))
+	records := server.Records("example.com")
+	assert.Len(t, records, 1)
+	assert.Equal(t, "www", records[0].Name)
+
+	_, err = server.Client(api.WithSnapshots(dir, "yaml"))
+	assert.NotNil(t, err)
+}
+
+func TestSetAndDeleteDomainRecordsSnapshot(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.AType, Name: "www", Data: "10.0.0.1", TTL: 3600},
+		&api.DomainRecord{Type: api.AType, Name: "api", Data: "10.0.0.2", TTL: 3600},
+		&api.DomainRecord{Type: api.TXTType, Name: "api", Data: "remove me", TTL: 3600},
+	)
+
+	dir := t.TempDir()
+	client, err := server.Client(api.WithSnapshots(dir, api.SnapshotJSON))
+	assert.Nil(t, err)
+	countSnapshots := func() int {
+		snapshots, err := filepath.Glob(filepath.Join(dir, "example.com-*.json"))
+		assert.Nil(t, err)
+		return len(snapshots)
+	}
+
+	a, _ := api.NewDomainRecord("www", api.AType, "10.0.0.3", 3600)
+	assert.Nil(t, client.SetDomainRecords("", "example.com", api.AType, "www", []*api.DomainRecord{a}))
+	assert.Equal(t, 1, countSnapshots())
+
+	assert.Nil(t, client.DeleteDomainRecords("", "example.com", api.TXTType, "api"))
+	assert.Equal(t, 2, countSnapshots())
+
+	// a series of changes is snapshotted once
+	once, err := api.SnapshotOnce(client, "", "example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, once.DeleteDomainRecords("", "example.com", api.AType, "www"))
+	assert.Nil(t, once.DeleteDomainRecords("", "example.com", api.AType, "api"))
+	assert.Equal(t, 3, countSnapshots())
+	assert.Len(t, server.Records("example.com"), 0)
+}
+
+func TestUpdateDomainRecordsCAA(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.CAAType, Name: api.Ptr, Data: `0 issue "digicert.com"`, TTL: 3600},
+		&api.DomainRecord{Type: api.CAAType, Name: "www", Data: `0 issue "digicert.com"`, TTL: 3600},
+	)
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	caa, _ := api.NewDomainRecord(api.Ptr, api.CAAType, `0 issue "letsencrypt.org"`, 3600)
+	assert.Nil(t, client.UpdateDomainRecords("", "example.com", []*api.DomainRecord{caa}))
+
+	records := server.Records("example.com")
+	assert.Len(t, records, 1)
+	assert.Equal(t, `0 issue "letsencrypt.org"`, records[0].Data)
+
+	a, _ := api.NewARecord("127.0.0.1")
+	assert.Nil(t, client.UpdateDomainRecords("", "example.com", []*api.DomainRecord{a}))
+
+	records = server.Records("example.com")
+	assert.Len(t, records, 1)
+	assert.Equal(t, api.AType, records[0].Type)
+
+	deletes := 0
+	for _, req := range server.Requests() {
+		if req.Method == http.MethodDelete {
+			deletes++
+		}
+	}
+	assert.Equal(t, 1, deletes)
+}
+
+func TestValidationErrors(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com")
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	rec, _ := api.NewDomainRecord("www", api.AType, "127.0.0.1", 60)
+	err = client.UpdateDomainRecords("", "example.com", []*api.DomainRecord{rec})
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "[422:INVALID_BODY]"), err.Error())
+		assert.Contains(t, err.Error(), "records[0].ttl")
+	}
+}
+
+func TestAuthenticationFailure(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com")
+
+	client, err := api.NewClient(server.URL, "bogus", "bogus", api.WithRateLimiter(nil))
+	assert.Nil(t, err)
+
+	_, err = client.GetDomainRecords("", "example.com")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "UNABLE_TO_AUTHENTICATE")
+	}
+}
+
+func TestRateLimitRetries(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com")
+	server.ThrottleNext(1, 0)
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+	_, err = client.GetDomain("", "example.com")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "[429:TOO_MANY_REQUESTS]")
+	}
+
+	server.ThrottleNext(2, 0)
+	client, err = server.Client(api.WithRetryPolicy(api.RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}))
+	assert.Nil(t, err)
+	_, err = client.GetDomain("", "example.com")
+	assert.Nil(t, err)
+}
+
+func TestServerErrors(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com")
+	server.FailNext(http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "boom")
+
+	client, err := server.Client()
+	assert.Nil(t, err)
+
+	_, err = client.GetDomain("", "example.com")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "[500:INTERNAL_SERVER_ERROR] boom", err.Error())
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RecordDiff describes the changes required to turn one record set into
+// another
+type RecordDiff struct {
+	Added   []*DomainRecord
+	Removed []*DomainRecord
+}
+
+// Empty is a predicate to determine whether the record sets are equivalent
+func (d RecordDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// String renders the diff with one record per line, prefixed by + or -
+func (d RecordDiff) String() string {
+	lines := make([]string, 0, len(d.Added)+len(d.Removed))
+	for _, rec := range d.Removed {
+		lines = append(lines, "- "+rec.String())
+	}
+	for _, rec := range d.Added {
+		lines = append(lines, "+ "+rec.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffRecords compares two record sets. Records are considered equal when all
+// of their attributes match, and duplicates are counted individually.
+func DiffRecords(current, desired []*DomainRecord) RecordDiff {
+	counts := make(map[string]int)
+	for _, rec := range current {
+		counts[rec.key()]++
+	}
+
+	var diff RecordDiff
+	for _, rec := range desired {
+		k := rec.key()
+		if counts[k] > 0 {
+			counts[k]--
+			continue
+		}
+		diff.Added = append(diff.Added, rec)
+	}
+	for i := len(current) - 1; i >= 0; i-- {
+		k := current[i].key()
+		if counts[k] > 0 {
+			counts[k]--
+			diff.Removed = append(diff.Removed, current[i])
+		}
+	}
+
+	sortRecords(diff.Added)
+	sortRecords(diff.Removed)
+	return diff
+}
+
+// HashRecords returns a digest of a record set that changes whenever a
+// record is added, removed or modified. It is independent of record order
+// and of presentation differences such as name qualification or TXT quoting.
+func HashRecords(domain string, records []*DomainRecord) string {
+	canonical := CanonicalRecords(domain, records)
+	keys := make([]string, len(canonical))
+	for i, rec := range canonical {
+		keys[i] = rec.key()
+	}
+	sort.Strings(keys)
+
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// CanonicalRecords returns copies of the records with normalized names and
+// data, so that equivalent records compare as equal. Names that cannot be
+// normalized are left as-is.
+func CanonicalRecords(domain string, records []*DomainRecord) []*DomainRecord {
+	canonical := make([]*DomainRecord, len(records))
+	for i, rec := range records {
+		copied := *rec
+		copied.Type = strings.ToUpper(rec.Type)
+		if name, err := NormalizeName(domain, rec.Name); err == nil {
+			copied.Name = name
+		}
+		copied.Data = NormalizeData(copied.Type, rec.Data)
+		canonical[i] = &copied
+	}
+	return canonical
+}
+
+// String renders a record in a compact, human readable form
+func (r *DomainRecord) String() string {
+	s := fmt.Sprintf("%s %s %s (ttl=%d", r.Type, r.Name, r.Data, r.TTL)
+	switch r.Type {
+	case MXType:
+		s += fmt.Sprintf(", priority=%d", r.Priority)
+	case SRVType:
+		s += fmt.Sprintf(", service=%s, protocol=%s, priority=%d, weight=%d, port=%d",
+			r.Service, r.Protocol, r.Priority, r.Weight, r.port())
+	}
+	return s + ")"
+}
+
+func (r *DomainRecord) key() string {
+	return fmt.Sprintf("%s|%s|%s|%d|%d|%d|%d|%s|%s",
+		strings.ToUpper(r.Type), strings.ToLower(r.Name), r.Data, r.TTL,
+		r.Priority, r.Weight, r.port(), r.Service, r.Protocol)
+}
+
+func (r *DomainRecord) port() int {
+	if r.Port == nil {
+		return 0
+	}
+	return *r.Port
+}
+
+func sortRecords(records []*DomainRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].key() < records[j].key()
+	})
+}
@@ -0,0 +1,53 @@
+package api
+
+import "testing"
+
+func TestDiffRecords(t *testing.T) {
+	current := []*DomainRecord{
+		{Type: AType, Name: Ptr, Data: "192.168.1.2", TTL: DefaultTTL},
+		{Type: AType, Name: Ptr, Data: "192.168.1.3", TTL: DefaultTTL},
+		{Type: CNameType, Name: "www", Data: Ptr, TTL: DefaultTTL},
+	}
+	desired := []*DomainRecord{
+		{Type: AType, Name: Ptr, Data: "192.168.1.2", TTL: DefaultTTL},
+		{Type: CNameType, Name: "WWW", Data: Ptr, TTL: DefaultTTL},
+		{Type: MXType, Name: Ptr, Data: "mail.example.com", TTL: 600, Priority: 10},
+	}
+
+	diff := DiffRecords(current, desired)
+	if len(diff.Added) != 1 || diff.Added[0].Type != MXType {
+		t.Errorf("unexpected additions: %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Data != "192.168.1.3" {
+		t.Errorf("unexpected removals: %v", diff.Removed)
+	}
+	if diff.Empty() {
+		t.Error("expected a non-empty diff")
+	}
+	if !DiffRecords(current, current).Empty() {
+		t.Error("expected identical record sets to produce an empty diff")
+	}
+}
+
+func TestHashRecords(t *testing.T) {
+	records := []*DomainRecord{
+		{Type: AType, Name: "www", Data: "10.0.0.1", TTL: DefaultTTL},
+		{Type: TXTType, Name: Ptr, Data: "v=spf1 -all", TTL: DefaultTTL},
+	}
+	equivalent := []*DomainRecord{
+		{Type: TXTType, Name: "example.com.", Data: `"v=spf1 -all"`, TTL: DefaultTTL},
+		{Type: AType, Name: "WWW", Data: "10.0.0.1", TTL: DefaultTTL},
+	}
+	changed := []*DomainRecord{
+		{Type: AType, Name: "www", Data: "10.0.0.1", TTL: 600},
+		{Type: TXTType, Name: Ptr, Data: "v=spf1 -all", TTL: DefaultTTL},
+	}
+
+	hash := HashRecords("example.com", records)
+	if got := HashRecords("example.com", equivalent); got != hash {
+		t.Error("expected equivalent records to have the same hash")
+	}
+	if got := HashRecords("example.com", changed); got == hash {
+		t.Error("expected a changed TTL to change the hash")
+	}
+}
@@ -4,16 +4,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 )
 
 const (
 	defaultLimit = 500
-  
+
 	pathDomainRecords       = "%s/v1/domains/%s/records?limit=%d&offset=%d"
 	pathDomainRecordsByType = "%s/v1/domains/%s/records/%s"
+	pathDomainRecordsByName = "%s/v1/domains/%s/records/%s/%s"
 	pathDomains             = "%s/v1/domains/%s"
 )
 
@@ -36,7 +38,12 @@ func (c *Client) GetDomains(customerID string) ([]Domain, error) {
 
 // GetDomain fetches the details for the provided domain
 func (c *Client) GetDomain(customerID, domain string) (*Domain, error) {
-	domainURL := fmt.Sprintf(pathDomains, c.baseURL, domain)
+	segment, err := domainSegment(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	domainURL := fmt.Sprintf(pathDomains, c.baseURL, segment)
 	req, err := http.NewRequest(http.MethodGet, domainURL, nil)
 
 	if err != nil {
@@ -53,11 +60,16 @@ func (c *Client) GetDomain(customerID, domain string) (*Domain, error) {
 
 // GetDomainRecords fetches all existing records for the provided domain
 func (c *Client) GetDomainRecords(customerID, domain string) ([]*DomainRecord, error) {
+	segment, err := domainSegment(domain)
+	if err != nil {
+		return nil, err
+	}
+
 	offset := 1
 	records := make([]*DomainRecord, 0)
 	for {
 		page := make([]*DomainRecord, 0)
-		domainURL := fmt.Sprintf(pathDomainRecords, c.baseURL, domain, defaultLimit, offset)
+		domainURL := fmt.Sprintf(pathDomainRecords, c.baseURL, segment, defaultLimit, offset)
 		req, err := http.NewRequest(http.MethodGet, domainURL, nil)
 
 		if err != nil {
@@ -79,9 +91,24 @@ func (c *Client) GetDomainRecords(customerID, domain string) ([]*DomainRecord, e
 
 // UpdateDomainRecords adds records or replaces all existing records for the provided domain
 func (c *Client) UpdateDomainRecords(customerID, domain string, records []*DomainRecord) error {
-	for t := range supportedTypes {
-		typeRecords := c.domainRecordsOfType(t, records)
+	segment, err := domainSegment(domain)
+	if err != nil {
+		return err
+	}
+	records, err = encodeRecords(domain, records)
+	if err != nil {
+		return err
+	}
+	if err := c.snapshot(customerID, domain); err != nil {
+		return err
+	}
+
+	for _, t := range ReplacedTypes(records) {
+		typeRecords := domainRecordsOfType(t, records)
 		if IsDisallowed(t, typeRecords) {
+			if err := c.deleteDomainRecordsOfType(customerID, domain, segment, t); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -90,12 +117,10 @@ func (c *Client) UpdateDomainRecords(customerID, domain string, records []*Domai
 			return err
 		}
 
-		domainURL := fmt.Sprintf(pathDomainRecordsByType, c.baseURL, domain, t)
+		domainURL := fmt.Sprintf(pathDomainRecordsByType, c.baseURL, segment, url.PathEscape(t))
 		buffer := bytes.NewBuffer(msg)
 
-		log.Println(domainURL)
-		log.Println(buffer)
-
+		c.logger.Debug("replacing domain records", "domain", domain, "type", t, "count", len(typeRecords))
 		req, err := http.NewRequest(http.MethodPut, domainURL, buffer)
 		if err != nil {
 			return err
@@ -109,7 +134,199 @@ func (c *Client) UpdateDomainRecords(customerID, domain string, records []*Domai
 	return nil
 }
 
-func (c *Client) domainRecordsOfType(t string, records []*DomainRecord) []*DomainRecord {
+// SetDomainRecords replaces all existing records of the type and name for the
+// provided domain
+func (c *Client) SetDomainRecords(customerID, domain, t, name string, records []*DomainRecord) error {
+	if err := c.snapshot(customerID, domain); err != nil {
+		return err
+	}
+	return c.setDomainRecords(customerID, domain, t, name, records)
+}
+
+func (c *Client) setDomainRecords(customerID, domain, t, name string, records []*DomainRecord) error {
+	segment, err := domainSegment(domain)
+	if err != nil {
+		return err
+	}
+	if name, err = NormalizeName(domain, name); err != nil {
+		return err
+	}
+	records, err = encodeRecords(domain, records)
+	if err != nil {
+		return err
+	}
+
+	msg, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	domainURL := fmt.Sprintf(pathDomainRecordsByName, c.baseURL, segment, url.PathEscape(t), url.PathEscape(name))
+	c.logger.Debug("replacing domain records", "domain", domain, "type", t, "name", name, "count", len(records))
+	req, err := http.NewRequest(http.MethodPut, domainURL, bytes.NewBuffer(msg))
+	if err != nil {
+		return err
+	}
+
+	return c.execute(customerID, req, nil)
+}
+
+// DeleteDomainRecords removes all existing records of the type and name for
+// the provided domain
+func (c *Client) DeleteDomainRecords(customerID, domain, t, name string) error {
+	if err := c.snapshot(customerID, domain); err != nil {
+		return err
+	}
+	return c.deleteDomainRecords(customerID, domain, t, name)
+}
+
+func (c *Client) deleteDomainRecords(customerID, domain, t, name string) error {
+	segment, err := domainSegment(domain)
+	if err != nil {
+		return err
+	}
+	if name, err = NormalizeName(domain, name); err != nil {
+		return err
+	}
+
+	domainURL := fmt.Sprintf(pathDomainRecordsByName, c.baseURL, segment, url.PathEscape(t), url.PathEscape(name))
+	c.logger.Debug("deleting domain records", "domain", domain, "type", t, "name", name)
+	req, err := http.NewRequest(http.MethodDelete, domainURL, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.execute(customerID, req, nil)
+}
+
+// SnapshotOnce stores the current records of the domain when snapshots are
+// enabled, and returns a client that applies a series of changes to it
+// without snapshotting each one
+func (c *Client) SnapshotOnce(customerID, domain string) (DNSClient, error) {
+	if err := c.snapshot(customerID, domain); err != nil {
+		return nil, err
+	}
+	unsnapshotted := *c
+	unsnapshotted.snapshotDir = ""
+	return &unsnapshotted, nil
+}
+
+// snapshot stores the current records of the domain when snapshots are
+// enabled, so that a change can be rolled back
+func (c *Client) snapshot(customerID, domain string) error {
+	if c.snapshotDir == "" {
+		return nil
+	}
+
+	records, err := c.GetDomainRecords(customerID, domain)
+	if err != nil {
+		return fmt.Errorf("couldn't snapshot domain records (%s): %s", domain, err)
+	}
+	path, err := WriteSnapshot(c.snapshotDir, c.snapshotFormat, domain, records)
+	if err != nil {
+		return fmt.Errorf("couldn't snapshot domain records (%s): %s", domain, err)
+	}
+	c.logger.Info("saved domain records snapshot", "domain", domain, "path", path)
+	return nil
+}
+
+// deleteDomainRecordsOfType removes the existing records of a type one name
+// at a time, since the type cannot be replaced with an empty list. The caller
+// takes the snapshot.
+func (c *Client) deleteDomainRecordsOfType(customerID, domain, segment, t string) error {
+	domainURL := fmt.Sprintf(pathDomainRecordsByType, c.baseURL, segment, url.PathEscape(t))
+	req, err := http.NewRequest(http.MethodGet, domainURL, nil)
+	if err != nil {
+		return err
+	}
+
+	existing := make([]*DomainRecord, 0)
+	if err := c.execute(customerID, req, &existing); err != nil {
+		return err
+	}
+
+	deleted := make(map[string]bool)
+	for _, rec := range existing {
+		if deleted[rec.Name] {
+			continue
+		}
+		if err := c.deleteDomainRecords(customerID, domain, t, rec.Name); err != nil {
+			return err
+		}
+		deleted[rec.Name] = true
+	}
+	return nil
+}
+
+// ReplacedTypes returns the record types that UpdateDomainRecords replaces
+// when applying the supplied records, in alphabetical order
+func ReplacedTypes(records []*DomainRecord) []string {
+	types := make([]string, 0, len(supportedTypes))
+	for t := range supportedTypes {
+		if !IsDisallowed(t, domainRecordsOfType(t, records)) || IsDeletedWhenEmpty(t) {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ReplacedRecords returns the existing records that UpdateDomainRecords
+// overwrites when applying the desired records
+func ReplacedRecords(existing, desired []*DomainRecord) []*DomainRecord {
+	replaced := make([]*DomainRecord, 0)
+	for _, t := range ReplacedTypes(desired) {
+		replaced = append(replaced, domainRecordsOfType(t, existing)...)
+	}
+	return replaced
+}
+
+// AppliedRecords returns the records that remain once UpdateDomainRecords
+// has applied the desired records to the existing ones
+func AppliedRecords(existing, desired []*DomainRecord) []*DomainRecord {
+	replaced := make(map[string]bool)
+	for _, t := range ReplacedTypes(desired) {
+		replaced[t] = true
+	}
+
+	applied := make([]*DomainRecord, 0, len(existing)+len(desired))
+	for _, rec := range existing {
+		if !replaced[strings.ToUpper(rec.Type)] {
+			applied = append(applied, rec)
+		}
+	}
+	return append(applied, ReplacedRecords(desired, desired)...)
+}
+
+// encodeRecords prepares records for a request, normalizing names and
+// chunking long TXT values into character-strings
+func encodeRecords(domain string, records []*DomainRecord) ([]*DomainRecord, error) {
+	encoded, err := NormalizeRecords(domain, records)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range encoded {
+		switch {
+		case strings.EqualFold(rec.Type, TXTType):
+			rec.Data = EncodeTXT(TXTValue(rec.Data))
+		case isTargetType(rec.Type):
+			rec.Data = NormalizeData(rec.Type, rec.Data)
+		}
+	}
+	return encoded, nil
+}
+
+// domainSegment converts a domain into its A-label form and escapes it for
+// use as a URL path segment
+func domainSegment(domain string) (string, error) {
+	ascii, err := ToASCII(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), ".")))
+	if err != nil {
+		return "", err
+	}
+	return url.PathEscape(ascii), nil
+}
+
+func domainRecordsOfType(t string, records []*DomainRecord) []*DomainRecord {
 	typeRecords := make([]*DomainRecord, 0)
 
 	for _, record := range records {
@@ -0,0 +1,249 @@
+package godaddytest
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNSServer is a minimal UDP and TCP DNS server for testing nameserver
+// checks. It acts as both the recursive resolver, answering A and AAAA
+// queries for registered hosts, and as every nameserver, answering SOA
+// queries authoritatively for registered zones.
+type DNSServer struct {
+	// Addr is the host:port on which the server listens
+	Addr string
+
+	conn     net.PacketConn
+	listener net.Listener
+	hosts    map[string][]net.IP
+	zones    map[string]bool
+	truncate bool
+	wg       sync.WaitGroup
+	sync.Mutex
+}
+
+// NewDNSServer starts a DNS server on a random local port. The caller should
+// call Close when finished, to shut it down.
+func NewDNSServer() *DNSServer {
+	conn, listener, err := listenDNS()
+	if err != nil {
+		panic(fmt.Sprintf("godaddytest: failed to listen on a port: %v", err))
+	}
+
+	s := &DNSServer{
+		Addr:     conn.LocalAddr().String(),
+		conn:     conn,
+		listener: listener,
+		hosts:    make(map[string][]net.IP),
+		zones:    make(map[string]bool),
+	}
+	s.wg.Add(2)
+	go s.serve()
+	go s.serveTCP()
+	return s
+}
+
+// listenDNS listens for UDP and TCP on the same random local port
+func listenDNS() (net.PacketConn, net.Listener, error) {
+	var err error
+	for attempt := 0; attempt < 10; attempt++ {
+		var conn net.PacketConn
+		conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var listener net.Listener
+		listener, err = net.Listen("tcp", conn.LocalAddr().String())
+		if err == nil {
+			return conn, listener, nil
+		}
+		conn.Close()
+	}
+	return nil, nil, err
+}
+
+// Port returns the port on which the server listens, for use as the
+// nameserver port
+func (s *DNSServer) Port() int {
+	return s.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// AddHost registers the address of a host. Hosts default to the server's
+// own address, so that the server also answers as their nameserver.
+func (s *DNSServer) AddHost(name string, ips ...net.IP) {
+	if len(ips) == 0 {
+		ips = []net.IP{net.IPv4(127, 0, 0, 1)}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.hosts[dnsKey(name)] = ips
+}
+
+// SetTruncate makes the server answer UDP queries with empty, truncated
+// responses, so that clients must retry over TCP
+func (s *DNSServer) SetTruncate(truncate bool) {
+	s.Lock()
+	defer s.Unlock()
+	s.truncate = truncate
+}
+
+// AddZone registers a zone for which the server answers authoritatively
+func (s *DNSServer) AddZone(name string) {
+	s.Lock()
+	defer s.Unlock()
+	s.zones[dnsKey(name)] = true
+}
+
+// Close shuts down the server
+func (s *DNSServer) Close() {
+	s.conn.Close()
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *DNSServer) serve() {
+	defer s.wg.Done()
+
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		var query dnsmessage.Message
+		if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) != 1 {
+			continue
+		}
+		reply := s.answer(query)
+		if s.truncated() {
+			reply.Truncated = true
+			reply.Answers = nil
+		}
+		packed, err := reply.Pack()
+		if err != nil {
+			continue
+		}
+		s.conn.WriteTo(packed, addr)
+	}
+}
+
+func (s *DNSServer) serveTCP() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go s.handleTCP(conn)
+	}
+}
+
+// handleTCP answers the length-prefixed queries of a TCP connection
+func (s *DNSServer) handleTCP(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	for {
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		buf := make([]byte, int(length[0])<<8|int(length[1]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+
+		var query dnsmessage.Message
+		if err := query.Unpack(buf); err != nil || len(query.Questions) != 1 {
+			return
+		}
+		reply := s.answer(query)
+		packed, err := reply.Pack()
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write(append([]byte{byte(len(packed) >> 8), byte(len(packed))}, packed...)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *DNSServer) truncated() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.truncate
+}
+
+func (s *DNSServer) answer(query dnsmessage.Message) dnsmessage.Message {
+	s.Lock()
+	defer s.Unlock()
+
+	q := query.Questions[0]
+	reply := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 query.ID,
+			Response:           true,
+			RecursionDesired:   query.RecursionDesired,
+			RecursionAvailable: true,
+		},
+		Questions: query.Questions,
+	}
+	rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 600}
+
+	switch key := dnsKey(q.Name.String()); q.Type {
+	case dnsmessage.TypeA, dnsmessage.TypeAAAA:
+		ips, ok := s.hosts[key]
+		if !ok {
+			reply.RCode = dnsmessage.RCodeNameError
+			break
+		}
+		for _, ip := range ips {
+			v4 := ip.To4()
+			switch {
+			case q.Type == dnsmessage.TypeA && v4 != nil:
+				var a [4]byte
+				copy(a[:], v4)
+				reply.Answers = append(reply.Answers, dnsmessage.Resource{Header: rh, Body: &dnsmessage.AResource{A: a}})
+			case q.Type == dnsmessage.TypeAAAA && v4 == nil:
+				var aaaa [16]byte
+				copy(aaaa[:], ip.To16())
+				reply.Answers = append(reply.Answers, dnsmessage.Resource{Header: rh, Body: &dnsmessage.AAAAResource{AAAA: aaaa}})
+			}
+		}
+	case dnsmessage.TypeSOA:
+		if !s.zones[key] {
+			reply.RCode = dnsmessage.RCodeRefused
+			break
+		}
+		reply.Authoritative = true
+		reply.Answers = append(reply.Answers, dnsmessage.Resource{
+			Header: rh,
+			Body: &dnsmessage.SOAResource{
+				NS:      dnsmessage.MustNewName("ns1." + key + "."),
+				MBox:    dnsmessage.MustNewName("hostmaster." + key + "."),
+				Serial:  1,
+				Refresh: 28800,
+				Retry:   7200,
+				Expire:  604800,
+				MinTTL:  600,
+			},
+		})
+	default:
+		reply.RCode = dnsmessage.RCodeNotImplemented
+	}
+	return reply
+}
+
+func dnsKey(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
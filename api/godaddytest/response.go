@@ -0,0 +1,54 @@
+package godaddytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// field describes a validation failure of a single request attribute
+type field struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Path    string `json:"path"`
+}
+
+// errorResponse mirrors the error payload returned by the GoDaddy API
+type errorResponse struct {
+	Code          string  `json:"code"`
+	Message       string  `json:"message"`
+	Fields        []field `json:"fields,omitempty"`
+	RetryAfterSec int     `json:"retryAfterSec,omitempty"`
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	defer r.Body.Close()
+	return io.ReadAll(r.Body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b.Bytes())
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string, fields []field) {
+	resp := errorResponse{Code: code, Message: message, Fields: fields}
+	if status == http.StatusTooManyRequests {
+		resp.RetryAfterSec = 30
+	}
+	writeJSON(w, status, resp)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed", nil)
+}
@@ -0,0 +1,576 @@
+// Package godaddytest provides an in-process emulation of the GoDaddy domains
+// API for use in tests, similar in spirit to net/http/httptest.
+package godaddytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const (
+	// Key is the API key accepted by the server unless overridden
+	Key = "test-key"
+	// Secret is the API secret accepted by the server unless overridden
+	Secret = "test-secret"
+
+	headerCustomerID = "X-Shopper-Id"
+	pathPrefix       = "/v1/domains"
+)
+
+// Request captures the details of a request received by the server
+type Request struct {
+	Method     string
+	Path       string
+	Query      string
+	CustomerID string
+	Body       string
+}
+
+// Server emulates the GoDaddy /v1/domains API. Records are validated with
+// the same rules that GoDaddy applies and errors are returned using
+// GoDaddy's error payload format.
+type Server struct {
+	*httptest.Server
+
+	// Key and Secret are the credentials expected in the Authorization header
+	Key    string
+	Secret string
+	// MaxPageSize caps the limit requested when listing records
+	MaxPageSize int
+
+	domains   map[string]*domain
+	nextID    int64
+	requests  []Request
+	faults    []fault
+	rateLimit int
+	window    time.Duration
+	history   []time.Time
+	sync.Mutex
+}
+
+type domain struct {
+	customerID string
+	info       api.Domain
+	records    []*api.DomainRecord
+}
+
+type fault struct {
+	status     int
+	code       string
+	message    string
+	retryAfter int
+}
+
+// NewServer starts a new fake GoDaddy API server. The caller should call
+// Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		Key:         Key,
+		Secret:      Secret,
+		MaxPageSize: 500,
+		domains:     make(map[string]*domain),
+		nextID:      1000,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client constructs an api.Client for the server without rate limiting
+func (s *Server) Client(opts ...api.ClientOpt) (*api.Client, error) {
+	opts = append([]api.ClientOpt{api.WithRateLimiter(nil)}, opts...)
+	return api.NewClient(s.URL, s.Key, s.Secret, opts...)
+}
+
+// AddDomain registers an active domain with the supplied records
+func (s *Server) AddDomain(name string, records ...*api.DomainRecord) api.Domain {
+	return s.AddCustomerDomain("", name, records...)
+}
+
+// AddCustomerDomain registers an active domain owned by the customer. The
+// domain is only accessible when the matching X-Shopper-Id is supplied.
+func (s *Server) AddCustomerDomain(customerID, name string, records ...*api.DomainRecord) api.Domain {
+	s.Lock()
+	defer s.Unlock()
+
+	d := &domain{
+		customerID: customerID,
+		info: api.Domain{
+			ID:     s.nextID,
+			Name:   strings.ToLower(name),
+			Status: api.StatusActive,
+		},
+		records: copyRecords(records),
+	}
+	s.nextID++
+	s.domains[d.info.Name] = d
+	return d.info
+}
+
+// Records returns a copy of the records currently stored for the domain
+func (s *Server) Records(name string) []*api.DomainRecord {
+	s.Lock()
+	defer s.Unlock()
+
+	if d, ok := s.domains[strings.ToLower(name)]; ok {
+		return copyRecords(d.records)
+	}
+	return nil
+}
+
+// SetRecords overwrites the records stored for the domain, bypassing
+// validation. It is useful for simulating out-of-band changes.
+func (s *Server) SetRecords(name string, records ...*api.DomainRecord) {
+	s.Lock()
+	defer s.Unlock()
+
+	if d, ok := s.domains[strings.ToLower(name)]; ok {
+		d.records = copyRecords(records)
+	}
+}
+
+// Requests returns the requests received by the server, in order
+func (s *Server) Requests() []Request {
+	s.Lock()
+	defer s.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// FailNext causes the next request to fail with the supplied error
+func (s *Server) FailNext(status int, code, message string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.faults = append(s.faults, fault{status: status, code: code, message: message})
+}
+
+// ThrottleNext causes the next n requests to be rejected with a 429
+func (s *Server) ThrottleNext(n, retryAfterSec int) {
+	s.Lock()
+	defer s.Unlock()
+
+	for i := 0; i < n; i++ {
+		s.faults = append(s.faults, fault{
+			status:     http.StatusTooManyRequests,
+			code:       "TOO_MANY_REQUESTS",
+			message:    "Too many requests received within interval",
+			retryAfter: retryAfterSec,
+		})
+	}
+}
+
+// SetRateLimit rejects requests with a 429 once more than limit requests are
+// received within the window. A zero limit disables rate limiting.
+func (s *Server) SetRateLimit(limit int, window time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.rateLimit = limit
+	s.window = window
+	s.history = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	s.requests = append(s.requests, Request{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      r.URL.RawQuery,
+		CustomerID: r.Header.Get(headerCustomerID),
+		Body:       string(body),
+	})
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "UNABLE_TO_AUTHENTICATE", "Unable to authenticate the request", nil)
+		return
+	}
+	if f, ok := s.nextFault(); ok {
+		if f.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(f.retryAfter))
+		}
+		writeError(w, f.status, f.code, f.message, nil)
+		return
+	}
+	if s.throttled() {
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.window.Seconds())))
+		writeError(w, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "Too many requests received within interval", nil)
+		return
+	}
+
+	s.route(w, r, body)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	return r.Header.Get("Authorization") == fmt.Sprintf("sso-key %s:%s", s.Key, s.Secret)
+}
+
+func (s *Server) nextFault() (fault, bool) {
+	if len(s.faults) == 0 {
+		return fault{}, false
+	}
+	f := s.faults[0]
+	s.faults = s.faults[1:]
+	return f, true
+}
+
+func (s *Server) throttled() bool {
+	if s.rateLimit <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	recent := s.history[:0]
+	for _, t := range s.history {
+		if now.Sub(t) < s.window {
+			recent = append(recent, t)
+		}
+	}
+	s.history = append(recent, now)
+	return len(s.history) > s.rateLimit
+}
+
+// route dispatches requests of the form:
+//
+//	/v1/domains
+//	/v1/domains/{domain}
+//	/v1/domains/{domain}/records[/{type}[/{name}]]
+func (s *Server) route(w http.ResponseWriter, r *http.Request, body []byte) {
+	if !strings.HasPrefix(r.URL.Path, pathPrefix) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Not found", nil)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, pathPrefix), "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		segments = nil
+	}
+
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		s.listDomains(w, r)
+		return
+	case len(segments) == 0:
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	d, ok := s.domains[strings.ToLower(segments[0])]
+	if !ok || (d.customerID != "" && d.customerID != r.Header.Get(headerCustomerID)) {
+		writeError(w, http.StatusNotFound, "UNKNOWN_DOMAIN", "The given domain is not registered, or does not have a zone file", nil)
+		return
+	}
+
+	if len(segments) == 1 {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, domainDetail(d))
+		return
+	}
+
+	if segments[1] != "records" || len(segments) > 4 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Not found", nil)
+		return
+	}
+
+	var t, name string
+	if len(segments) > 2 {
+		t = strings.ToUpper(segments[2])
+	}
+	if len(segments) > 3 {
+		name = segments[3]
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.getRecords(w, r, d, t, name)
+	case http.MethodPut:
+		s.putRecords(w, d, t, name, body)
+	case http.MethodPatch:
+		s.patchRecords(w, d, t, name, body)
+	case http.MethodDelete:
+		s.deleteRecords(w, d, t, name)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) listDomains(w http.ResponseWriter, r *http.Request) {
+	customerID := r.Header.Get(headerCustomerID)
+	domains := make([]api.Domain, 0)
+	for _, d := range s.domains {
+		if d.customerID == "" || d.customerID == customerID {
+			domains = append(domains, d.info)
+		}
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].Name < domains[j].Name
+	})
+	writeJSON(w, http.StatusOK, domains)
+}
+
+// getRecords lists records, optionally filtered by type and name. The offset
+// query parameter is treated as a 1-based page index.
+func (s *Server) getRecords(w http.ResponseWriter, r *http.Request, d *domain, t, name string) {
+	records := filterRecords(d.records, t, name)
+
+	limit, err := queryInt(r, "limit", s.MaxPageSize)
+	if err != nil || limit < 1 {
+		writeError(w, http.StatusUnprocessableEntity, "INVALID_QUERY", "limit must be a positive integer", nil)
+		return
+	}
+	if limit > s.MaxPageSize {
+		limit = s.MaxPageSize
+	}
+	page, err := queryInt(r, "offset", 1)
+	if err != nil || page < 1 {
+		writeError(w, http.StatusUnprocessableEntity, "INVALID_QUERY", "offset must be a positive integer", nil)
+		return
+	}
+
+	start := (page - 1) * limit
+	if start > len(records) {
+		start = len(records)
+	}
+	end := start + limit
+	if end > len(records) {
+		end = len(records)
+	}
+	writeJSON(w, http.StatusOK, records[start:end])
+}
+
+func (s *Server) putRecords(w http.ResponseWriter, d *domain, t, name string, body []byte) {
+	records, ok := decodeRecords(w, body, t, name)
+	if !ok {
+		return
+	}
+	if t == api.NSType && name == "" && len(records) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "INVALID_BODY", "At least one NS record is required", nil)
+		return
+	}
+	if t == api.CAAType && len(records) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "INVALID_BODY", "At least one CAA record is required", nil)
+		return
+	}
+	if t == api.SOAType {
+		if msg := soaUpdateError(d.records, name, records); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, "INVALID_BODY", msg, nil)
+			return
+		}
+	}
+
+	switch {
+	case t == "":
+		d.records = records
+	case name == "":
+		d.records = append(excludeRecords(d.records, t, ""), records...)
+	default:
+		d.records = append(excludeRecords(d.records, t, name), records...)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) patchRecords(w http.ResponseWriter, d *domain, t, name string, body []byte) {
+	if t != "" || name != "" {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	records, ok := decodeRecords(w, body, "", "")
+	if !ok {
+		return
+	}
+	for i, rec := range records {
+		for _, existing := range d.records {
+			if sameRecord(rec, existing) {
+				writeError(w, http.StatusUnprocessableEntity, "DUPLICATE_RECORD", "Another record with the same attributes already exists", []field{
+					{Code: "DUPLICATE_RECORD", Message: "duplicate record", Path: fmt.Sprintf("records[%d]", i)},
+				})
+				return
+			}
+		}
+	}
+	d.records = append(d.records, records...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteRecords(w http.ResponseWriter, d *domain, t, name string) {
+	if t == "" || name == "" {
+		writeMethodNotAllowed(w)
+		return
+	}
+	if len(filterRecords(d.records, t, name)) == 0 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("No %s records found for %s", t, name), nil)
+		return
+	}
+	if t == api.NSType && name == api.Ptr {
+		writeError(w, http.StatusConflict, "CONFLICT", "Apex NS records cannot be deleted", nil)
+		return
+	}
+	d.records = excludeRecords(d.records, t, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeRecords parses and validates a request body. When the path selects a
+// type or name, records inherit them and must not contradict them.
+func decodeRecords(w http.ResponseWriter, body []byte, t, name string) ([]*api.DomainRecord, bool) {
+	records := make([]*api.DomainRecord, 0)
+	if err := json.Unmarshal(body, &records); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "INVALID_BODY", "Request body doesn't fulfill schema, see details in `fields`", []field{
+			{Code: "UNEXPECTED_TYPE", Message: err.Error(), Path: "records"},
+		})
+		return nil, false
+	}
+
+	var fields []field
+	for i, rec := range records {
+		if rec == nil {
+			fields = append(fields, field{Code: "MISSING", Message: "record must not be null", Path: fmt.Sprintf("records[%d]", i)})
+			continue
+		}
+		if t != "" {
+			if rec.Type != "" && !strings.EqualFold(rec.Type, t) {
+				fields = append(fields, field{Code: "MISMATCH", Message: "type does not match the request path", Path: fmt.Sprintf("records[%d].type", i)})
+			}
+			rec.Type = t
+		}
+		if name != "" {
+			if rec.Name != "" && rec.Name != name {
+				fields = append(fields, field{Code: "MISMATCH", Message: "name does not match the request path", Path: fmt.Sprintf("records[%d].name", i)})
+			}
+			rec.Name = name
+		}
+		rec.Type = strings.ToUpper(rec.Type)
+		fields = append(fields, validateRecord(i, rec)...)
+
+		for j := 0; j < i; j++ {
+			if records[j] != nil && sameRecord(records[j], rec) {
+				fields = append(fields, field{Code: "DUPLICATE_RECORD", Message: "duplicate record", Path: fmt.Sprintf("records[%d]", i)})
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, "INVALID_BODY", "Request body doesn't fulfill schema, see details in `fields`", fields)
+		return nil, false
+	}
+	return records, true
+}
+
+// soaUpdateError validates a replacement of the SOA record. The provider
+// assumes that only the timers may be changed, since the mname, rname and
+// serial are managed by GoDaddy, so the server refuses any other change.
+func soaUpdateError(existing []*api.DomainRecord, name string, records []*api.DomainRecord) string {
+	current := filterRecords(existing, api.SOAType, api.Ptr)
+	if name != api.Ptr || len(records) != 1 || len(current) != 1 {
+		return "Only the existing apex SOA record may be replaced"
+	}
+
+	before, err := api.ParseSOA(current[0].Data)
+	if err != nil {
+		return err.Error()
+	}
+	after, err := api.ParseSOA(records[0].Data)
+	if err != nil {
+		return err.Error()
+	}
+	if after.MName != before.MName || after.RName != before.RName || after.Serial != before.Serial {
+		return "Only the SOA refresh, retry, expire and minimum may be changed"
+	}
+	return ""
+}
+
+func domainDetail(d *domain) map[string]interface{} {
+	return map[string]interface{}{
+		"domainId": d.info.ID,
+		"domain":   d.info.Name,
+		"status":   d.info.Status,
+		"contactRegistrant": map[string]interface{}{
+			"nameFirst": "Jane",
+			"nameLast":  "Doe",
+			"email":     "jane.doe@example.com",
+			"phone":     "+1.4805058877",
+		},
+		"nameServers": nameservers(d.records),
+	}
+}
+
+func nameservers(records []*api.DomainRecord) []string {
+	ns := make([]string, 0)
+	for _, rec := range records {
+		if rec.Type == api.NSType && rec.Name == api.Ptr {
+			ns = append(ns, rec.Data)
+		}
+	}
+	return ns
+}
+
+func filterRecords(records []*api.DomainRecord, t, name string) []*api.DomainRecord {
+	result := make([]*api.DomainRecord, 0)
+	for _, rec := range records {
+		if matches(rec, t, name) {
+			result = append(result, rec)
+		}
+	}
+	return result
+}
+
+func excludeRecords(records []*api.DomainRecord, t, name string) []*api.DomainRecord {
+	result := make([]*api.DomainRecord, 0, len(records))
+	for _, rec := range records {
+		if !matches(rec, t, name) {
+			result = append(result, rec)
+		}
+	}
+	return result
+}
+
+func matches(rec *api.DomainRecord, t, name string) bool {
+	return (t == "" || strings.EqualFold(rec.Type, t)) && (name == "" || strings.EqualFold(rec.Name, name))
+}
+
+func sameRecord(a, b *api.DomainRecord) bool {
+	return strings.EqualFold(a.Type, b.Type) &&
+		strings.EqualFold(a.Name, b.Name) &&
+		a.Data == b.Data &&
+		a.Service == b.Service &&
+		a.Protocol == b.Protocol
+}
+
+func copyRecords(records []*api.DomainRecord) []*api.DomainRecord {
+	result := make([]*api.DomainRecord, len(records))
+	for i, rec := range records {
+		c := *rec
+		if rec.Port != nil {
+			port := *rec.Port
+			c.Port = &port
+		}
+		result[i] = &c
+	}
+	return result
+}
+
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,62 @@
+package godaddytest
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+func TestServerRecordEndpoints(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddDomain("example.com",
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: 3600},
+		&api.DomainRecord{Type: api.CNameType, Name: "www", Data: "@", TTL: 3600},
+		&api.DomainRecord{Type: api.SOAType, Name: api.Ptr, Data: "ns1.domaincontrol.com. dns.jomax.net. 2023071801 28800 7200 604800 600", TTL: 3600},
+	)
+
+	var criteria = []struct {
+		Name   string
+		Method string
+		Path   string
+		Body   string
+		Status int
+	}{
+		{"Given a type and name lookup", http.MethodGet, "/v1/domains/example.com/records/CNAME/www", "", http.StatusOK},
+		{"Given an unknown domain", http.MethodGet, "/v1/domains/unknown.com/records", "", http.StatusNotFound},
+		{"Given a valid patch", http.MethodPatch, "/v1/domains/example.com/records", `[{"type":"TXT","name":"@","data":"hello","ttl":600}]`, http.StatusOK},
+		{"Given a duplicate patch", http.MethodPatch, "/v1/domains/example.com/records", `[{"type":"TXT","name":"@","data":"hello","ttl":600}]`, http.StatusUnprocessableEntity},
+		{"Given an invalid A record", http.MethodPut, "/v1/domains/example.com/records/A", `[{"name":"@","data":"not-an-ip","ttl":600}]`, http.StatusUnprocessableEntity},
+		{"Given an empty NS replacement", http.MethodPut, "/v1/domains/example.com/records/NS", `[]`, http.StatusUnprocessableEntity},
+		{"Given an SOA timer change", http.MethodPut, "/v1/domains/example.com/records/SOA/@", `[{"data":"ns1.domaincontrol.com. dns.jomax.net. 2023071801 3600 7200 604800 600","ttl":3600}]`, http.StatusOK},
+		{"Given an SOA serial change", http.MethodPut, "/v1/domains/example.com/records/SOA/@", `[{"data":"ns1.domaincontrol.com. dns.jomax.net. 2023071802 3600 7200 604800 600","ttl":3600}]`, http.StatusUnprocessableEntity},
+		{"Given a name replacement", http.MethodPut, "/v1/domains/example.com/records/CNAME/www", `[{"data":"example.github.io","ttl":600}]`, http.StatusOK},
+		{"Given a delete", http.MethodDelete, "/v1/domains/example.com/records/TXT/@", "", http.StatusNoContent},
+		{"Given a missing delete", http.MethodDelete, "/v1/domains/example.com/records/TXT/@", "", http.StatusNotFound},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			req, err := http.NewRequest(test.Method, server.URL+test.Path, bytes.NewBufferString(test.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "sso-key "+Key+":"+Secret)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != test.Status {
+				t.Errorf("expected status %d, got %d", test.Status, resp.StatusCode)
+			}
+		})
+	}
+
+	records := server.Records("example.com")
+	if len(records) != 3 || records[2].Data != "example.github.io" {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
@@ -0,0 +1,121 @@
+package godaddytest
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const (
+	minTTL      = 600
+	maxTTL      = 604800
+	maxNameLen  = 255
+	maxLabelLen = 63
+	maxDataLen  = 255
+	maxTXTLen   = 512
+)
+
+var recordTypes = map[string]struct{}{
+	api.AType:     {},
+	api.AAAAType:  {},
+	api.CAAType:   {},
+	api.CNameType: {},
+	api.MXType:    {},
+	api.NSType:    {},
+	api.SOAType:   {},
+	api.SRVType:   {},
+	api.TXTType:   {},
+}
+
+// validateRecord applies GoDaddy's record validation rules, returning a
+// field error for each violation.
+func validateRecord(i int, rec *api.DomainRecord) []field {
+	var fields []field
+	invalid := func(attr, code, format string, args ...interface{}) {
+		fields = append(fields, field{
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+			Path:    fmt.Sprintf("records[%d].%s", i, attr),
+		})
+	}
+
+	if _, ok := recordTypes[rec.Type]; !ok {
+		invalid("type", "INVALID_ENUM", "type must be one of A, AAAA, CAA, CNAME, MX, NS, SOA, SRV, TXT")
+	}
+
+	switch {
+	case rec.Name == "":
+		invalid("name", "MISSING", "name is required")
+	case len(rec.Name) > maxNameLen:
+		invalid("name", "TOO_LONG", "name must not exceed %d characters", maxNameLen)
+	default:
+		for _, label := range strings.Split(rec.Name, ".") {
+			if len(label) > maxLabelLen {
+				invalid("name", "TOO_LONG", "name labels must not exceed %d characters", maxLabelLen)
+				break
+			}
+		}
+	}
+
+	maxLen, dataLen := maxDataLen, len(rec.Data)
+	if rec.Type == api.TXTType {
+		maxLen, dataLen = maxTXTLen, len(api.TXTValue(rec.Data))
+	}
+	switch {
+	case rec.Data == "":
+		invalid("data", "MISSING", "data is required")
+	case dataLen > maxLen:
+		invalid("data", "TOO_LONG", "data must not exceed %d characters", maxLen)
+	}
+
+	if rec.TTL < minTTL || rec.TTL > maxTTL {
+		invalid("ttl", "OUT_OF_RANGE", "ttl must be between %d and %d", minTTL, maxTTL)
+	}
+
+	switch rec.Type {
+	case api.AType:
+		if ip := net.ParseIP(rec.Data); ip == nil || ip.To4() == nil {
+			invalid("data", "INVALID_FORMAT", "data must be an IPv4 address")
+		}
+	case api.AAAAType:
+		if ip := net.ParseIP(rec.Data); ip == nil || ip.To4() != nil {
+			invalid("data", "INVALID_FORMAT", "data must be an IPv6 address")
+		}
+	case api.CAAType:
+		if err := api.ValidateCAA(rec.Data); err != nil {
+			invalid("data", "INVALID_FORMAT", "%s", err)
+		}
+	case api.SOAType:
+		if _, err := api.ParseSOA(rec.Data); err != nil {
+			invalid("data", "INVALID_FORMAT", "%s", err)
+		}
+	case api.CNameType:
+		if rec.Name == api.Ptr {
+			invalid("name", "INVALID_FORMAT", "CNAME records cannot be created at the apex")
+		}
+	case api.MXType:
+		if rec.Priority < 0 || rec.Priority > 65535 {
+			invalid("priority", "OUT_OF_RANGE", "priority must be between 0 and 65535")
+		}
+	case api.SRVType:
+		if !strings.HasPrefix(rec.Service, "_") {
+			invalid("service", "INVALID_FORMAT", "service must start with an underscore")
+		}
+		if !strings.HasPrefix(rec.Protocol, "_") {
+			invalid("protocol", "INVALID_FORMAT", "protocol must start with an underscore")
+		}
+		if rec.Port == nil || *rec.Port < 1 || *rec.Port > 65535 {
+			invalid("port", "OUT_OF_RANGE", "port must be between 1 and 65535")
+		}
+		if rec.Priority < 0 || rec.Priority > 65535 {
+			invalid("priority", "OUT_OF_RANGE", "priority must be between 0 and 65535")
+		}
+		if rec.Weight < 0 || rec.Weight > 65535 {
+			invalid("weight", "OUT_OF_RANGE", "weight must be between 0 and 65535")
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,164 @@
+// Package hclgen renders existing GoDaddy domain records as Terraform
+// configuration for the godaddy_domain_record resource, along with the
+// Terraform 1.5 import block that adopts the domain into state.
+package hclgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ResourceType is the Terraform resource type that is generated
+const ResourceType = "godaddy_domain_record"
+
+// Config describes the resource to generate
+type Config struct {
+	// Domain is the name of the domain (e.g. example.com)
+	Domain string
+	// Customer is the optional reseller customer ID
+	Customer string
+	// Name is the resource name; defaults to a name derived from Domain
+	Name string
+}
+
+// Marshal renders the records of a domain as Terraform configuration
+func Marshal(cfg Config, records []*api.DomainRecord) ([]byte, error) {
+	var b bytes.Buffer
+	if err := Write(&b, cfg, records); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Write renders an import block and a godaddy_domain_record resource for the
+// records of a domain. Records are bucketed into the addresses, nameservers
+// and record attributes exactly as the resource reads them, so that the first
+// plan after import is clean.
+func Write(w io.Writer, cfg Config, records []*api.DomainRecord) error {
+	domain := strings.TrimSuffix(strings.TrimSpace(cfg.Domain), ".")
+	if domain == "" {
+		return fmt.Errorf("domain is required")
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = ResourceName(domain)
+	}
+	if !hclsyntax.ValidIdentifier(name) {
+		return fmt.Errorf("invalid resource name: %s", name)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	imp := body.AppendNewBlock("import", nil).Body()
+	imp.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: ResourceType},
+		hcl.TraverseAttr{Name: name},
+	})
+	imp.SetAttributeValue("id", cty.StringVal(domain))
+	body.AppendNewline()
+
+	res := body.AppendNewBlock("resource", []string{ResourceType, name}).Body()
+	res.SetAttributeValue("domain", cty.StringVal(domain))
+	if cfg.Customer != "" {
+		res.SetAttributeValue("customer", cty.StringVal(cfg.Customer))
+	}
+
+	addresses, nameservers, others := api.PartitionRecords(records)
+	if len(addresses) > 0 {
+		res.SetAttributeValue("addresses", stringList(addresses))
+	}
+	if len(nameservers) > 0 {
+		res.SetAttributeValue("nameservers", stringList(nameservers))
+	}
+
+	sortRecords(others)
+	for _, rec := range others {
+		res.AppendNewline()
+		writeRecord(res.AppendNewBlock("record", nil).Body(), rec)
+	}
+
+	_, err := w.Write(hclwrite.Format(f.Bytes()))
+	return err
+}
+
+// ResourceName derives a Terraform resource name from a domain name
+// (e.g. example.com becomes example_com)
+func ResourceName(domain string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(domain) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_', c == '-':
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	name := b.String()
+	if name == "" || !isLetter(name[0]) && name[0] != '_' {
+		name = "domain_" + name
+	}
+	return name
+}
+
+// writeRecord renders a record block, omitting attributes that match the
+// schema defaults
+func writeRecord(body *hclwrite.Body, rec *api.DomainRecord) {
+	body.SetAttributeValue("name", cty.StringVal(rec.Name))
+	body.SetAttributeValue("type", cty.StringVal(rec.Type))
+	body.SetAttributeValue("data", cty.StringVal(api.NormalizeData(rec.Type, rec.Data)))
+	if rec.TTL != api.DefaultTTL {
+		body.SetAttributeValue("ttl", cty.NumberIntVal(int64(rec.TTL)))
+	}
+	if rec.Priority != api.DefaultPriority {
+		body.SetAttributeValue("priority", cty.NumberIntVal(int64(rec.Priority)))
+	}
+	if rec.Weight != api.DefaultWeight {
+		body.SetAttributeValue("weight", cty.NumberIntVal(int64(rec.Weight)))
+	}
+	if rec.Port != nil && *rec.Port != api.DefaultPort {
+		body.SetAttributeValue("port", cty.NumberIntVal(int64(*rec.Port)))
+	}
+	if rec.Service != "" {
+		body.SetAttributeValue("service", cty.StringVal(rec.Service))
+	}
+	if rec.Protocol != "" {
+		body.SetAttributeValue("protocol", cty.StringVal(rec.Protocol))
+	}
+}
+
+func stringList(list []string) cty.Value {
+	values := make([]cty.Value, len(list))
+	for i, v := range list {
+		values[i] = cty.StringVal(v)
+	}
+	return cty.ListVal(values)
+}
+
+func sortRecords(records []*api.DomainRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Data < b.Data
+	})
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
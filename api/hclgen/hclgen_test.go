@@ -0,0 +1,84 @@
+package hclgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+func TestMarshal(t *testing.T) {
+	port := 389
+	records := []*api.DomainRecord{
+		{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		{Type: api.NSType, Name: api.Ptr, Data: "ns2.domaincontrol.com", TTL: api.DefaultTTL},
+		{Type: api.AType, Name: api.Ptr, Data: "10.0.0.1", TTL: api.DefaultTTL},
+		{Type: api.AType, Name: api.Ptr, Data: "10.0.0.2", TTL: 600},
+		{Type: api.TXTType, Name: api.Ptr, Data: `v=spf1 include:"${x}" ~all`, TTL: api.DefaultTTL},
+		{Type: api.CNameType, Name: "www", Data: api.Ptr, TTL: api.DefaultTTL},
+		{Type: api.SRVType, Name: api.Ptr, Data: "ldap.example.com", TTL: api.DefaultTTL,
+			Service: "_ldap", Protocol: "_tcp", Priority: 10, Weight: 5, Port: &port},
+	}
+
+	b, err := Marshal(Config{Domain: "example.com", Customer: "1234"}, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+
+	for _, want := range []string{
+		"import {\n  to = godaddy_domain_record.example_com\n  id = \"example.com\"\n}",
+		`resource "godaddy_domain_record" "example_com" {`,
+		`customer    = "1234"`,
+		`addresses   = ["10.0.0.1"]`,
+		`nameservers = ["ns1.domaincontrol.com", "ns2.domaincontrol.com"]`,
+		`data = "10.0.0.2"`,
+		`ttl  = 600`,
+		`data = "v=spf1 include:\"$${x}\" ~all"`,
+		`port     = 389`,
+		`protocol = "_tcp"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "record {"); n != 4 {
+		t.Errorf("expected 4 record blocks, found %d:\n%s", n, out)
+	}
+	if strings.Contains(out, "ttl  = 3600") {
+		t.Errorf("expected default TTLs to be omitted:\n%s", out)
+	}
+
+	f, diags := hclsyntax.ParseConfig(b, "main.tf", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("generated configuration is invalid: %s", diags)
+	}
+	blocks := f.Body.(*hclsyntax.Body).Blocks
+	if len(blocks) != 2 || blocks[0].Type != "import" || blocks[1].Type != "resource" {
+		t.Errorf("expected an import and a resource block, got %d blocks", len(blocks))
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	if _, err := Marshal(Config{}, nil); err == nil {
+		t.Error("expected an error without a domain")
+	}
+	if _, err := Marshal(Config{Domain: "example.com", Name: "not valid"}, nil); err == nil {
+		t.Error("expected an error for an invalid resource name")
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	for domain, want := range map[string]string{
+		"example.com":       "example_com",
+		"Sub.Example.co.uk": "sub_example_co_uk",
+		"123.example":       "domain_123_example",
+		"xn--bcher-kva.ch":  "xn--bcher-kva_ch",
+	} {
+		if got := ResourceName(domain); got != want {
+			t.Errorf("ResourceName(%q) = %q, want %q", domain, got, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// ToASCII converts an internationalized domain name into its canonical
+// A-label (punycode) form, e.g. bücher.example becomes
+// xn--bcher-kva.example. Only labels containing non-ASCII characters are
+// converted, so service labels (e.g. _domainconnect), "@" and "*" are
+// preserved.
+func ToASCII(name string) (string, error) {
+	if isASCII(name) {
+		return name, nil
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		ascii, err := idna.Lookup.ToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid internationalized name %q: %s", name, err)
+		}
+		labels[i] = ascii
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// ToUnicode converts the A-labels of a domain name into their Unicode form
+// for display. Labels that cannot be converted are returned as-is.
+func ToUnicode(name string) string {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), "xn--") {
+			continue
+		}
+		if unicode, err := idna.Display.ToUnicode(label); err == nil {
+			labels[i] = unicode
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package api
+
+import "testing"
+
+func TestToASCII(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Input    string
+		Expected string
+		Negative bool
+	}{
+		{"Given an ASCII name", "www.example.com", "www.example.com", false},
+		{"Given the apex", Ptr, Ptr, false},
+		{"Given a service label", "_sip._tcp", "_sip._tcp", false},
+		{"Given a Unicode domain", "bücher.example", "xn--bcher-kva.example", false},
+		{"Given a Unicode label and a wildcard", "*.bücher", "*.xn--bcher-kva", false},
+		{"Given an A-label", "xn--bcher-kva.example", "xn--bcher-kva.example", false},
+		{"Given an invalid Unicode label", "a\u00a0b.example", "", true},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			ascii, err := ToASCII(test.Input)
+			if test.Negative {
+				if err == nil {
+					t.Errorf("expected %q to be rejected, got %q", test.Input, ascii)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ascii != test.Expected {
+				t.Errorf("expected %q, got %q", test.Expected, ascii)
+			}
+		})
+	}
+}
+
+func TestToUnicode(t *testing.T) {
+	if name := ToUnicode("www.xn--bcher-kva.example"); name != "www.bücher.example" {
+		t.Errorf("expected the A-label to be decoded, got %q", name)
+	}
+	if name := ToUnicode("www.example.com"); name != "www.example.com" {
+		t.Errorf("expected an ASCII name to be unchanged, got %q", name)
+	}
+	if name := ToUnicode("xn--a.example"); name != "xn--a.example" {
+		t.Errorf("expected an invalid A-label to be unchanged, got %q", name)
+	}
+}
+
+func TestNormalizeUnicodeName(t *testing.T) {
+	name, err := NormalizeName("bücher.example", "www.xn--bcher-kva.example.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "www" {
+		t.Errorf("expected a relative name, got %q", name)
+	}
+
+	if name, _ = NormalizeName("example.com", "Straße"); name != "xn--strae-oqa" {
+		t.Errorf("expected an A-label, got %q", name)
+	}
+}
+
+func TestNormalizeTargetData(t *testing.T) {
+	if data := NormalizeData(CNameType, "mail.bücher.example"); data != "mail.xn--bcher-kva.example" {
+		t.Errorf("expected an A-label target, got %q", data)
+	}
+	if err := ValidateData(MXType, "mail.bücher.example"); err != nil {
+		t.Errorf("expected a Unicode target to be valid: %s", err)
+	}
+}
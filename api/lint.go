@@ -0,0 +1,176 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity classifies the issues reported by LintRecords
+type Severity int
+
+const (
+	// SeverityWarning is reported for records that most resolvers tolerate,
+	// but that violate the RFCs
+	SeverityWarning Severity = iota
+	// SeverityError is reported for records that break resolution
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// LintIssue describes a record that violates the DNS specifications
+type LintIssue struct {
+	Severity Severity
+	Type     string
+	Name     string
+	Message  string
+}
+
+func (i LintIssue) Error() string {
+	return fmt.Sprintf("%s record %q: %s", i.Type, i.Name, i.Message)
+}
+
+// LintRecords checks a set of records for inconsistencies that GoDaddy
+// accepts, but that break resolvers:
+//
+//   - a CNAME at the apex (RFC 1912 section 2.4)
+//   - a CNAME alongside other records of the same name (RFC 1034 section 3.6.2)
+//   - MX, NS and SRV targets within the domain that are CNAMEs (RFC 2181
+//     section 10.3, RFC 2782)
+//
+// Issues are returned in a stable order, errors first.
+func LintRecords(domain string, records []*DomainRecord) []LintIssue {
+	records, err := NormalizeRecords(domain, records)
+	if err != nil {
+		return []LintIssue{{Severity: SeverityError, Message: err.Error()}}
+	}
+
+	byName := make(map[string][]*DomainRecord)
+	for _, rec := range records {
+		byName[rec.Name] = append(byName[rec.Name], rec)
+	}
+
+	var issues []LintIssue
+	for name, named := range byName {
+		cnames, others := countCNames(named)
+		switch {
+		case cnames == 0:
+			continue
+		case name == Ptr:
+			issues = append(issues, LintIssue{
+				Severity: SeverityError,
+				Type:     CNameType,
+				Name:     name,
+				Message:  "a CNAME is not permitted at the apex of the domain",
+			})
+		case cnames > 1:
+			issues = append(issues, LintIssue{
+				Severity: SeverityError,
+				Type:     CNameType,
+				Name:     name,
+				Message:  fmt.Sprintf("found %d CNAME records; a name may only have one", cnames),
+			})
+		}
+		if others > 0 && name != Ptr {
+			issues = append(issues, LintIssue{
+				Severity: SeverityError,
+				Type:     CNameType,
+				Name:     name,
+				Message:  fmt.Sprintf("a CNAME may not coexist with other records (%s)", strings.Join(otherTypes(named), ", ")),
+			})
+		}
+	}
+
+	for _, rec := range records {
+		if !isTargetType(rec.Type) || strings.EqualFold(rec.Type, CNameType) {
+			continue
+		}
+		target, ok := zoneTarget(domain, rec.Data)
+		if !ok {
+			continue
+		}
+		if cnames, _ := countCNames(byName[target]); cnames > 0 {
+			issues = append(issues, LintIssue{
+				Severity: SeverityWarning,
+				Type:     strings.ToUpper(rec.Type),
+				Name:     rec.Name,
+				Message:  fmt.Sprintf("target %s is a CNAME; %s records must point at a name with address records", rec.Data, strings.ToUpper(rec.Type)),
+			})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Message < b.Message
+	})
+	return issues
+}
+
+// LintErrors filters the issues with an error severity
+func LintErrors(issues []LintIssue) []LintIssue {
+	var errs []LintIssue
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+func countCNames(records []*DomainRecord) (cnames, others int) {
+	for _, rec := range records {
+		if strings.EqualFold(rec.Type, CNameType) {
+			cnames++
+		} else {
+			others++
+		}
+	}
+	return cnames, others
+}
+
+// otherTypes lists the distinct non-CNAME types of the records
+func otherTypes(records []*DomainRecord) []string {
+	seen := make(map[string]struct{})
+	var types []string
+	for _, rec := range records {
+		t := strings.ToUpper(rec.Type)
+		if _, ok := seen[t]; ok || t == CNameType {
+			continue
+		}
+		seen[t] = struct{}{}
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// zoneTarget resolves a record target to a name relative to the domain.
+// Targets outside of the domain are ignored, since their records cannot be
+// checked.
+func zoneTarget(domain, target string) (string, bool) {
+	domain, err := ToASCII(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), ".")))
+	if err != nil {
+		return "", false
+	}
+	target = strings.TrimSuffix(NormalizeData(CNameType, strings.ToLower(strings.TrimSpace(target))), ".")
+
+	switch {
+	case target == Ptr || target == domain:
+		return Ptr, true
+	case strings.HasSuffix(target, "."+domain):
+		return strings.TrimSuffix(target, "."+domain), true
+	}
+	return "", false
+}
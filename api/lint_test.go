@@ -0,0 +1,95 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintRecords(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Records  []*DomainRecord
+		Severity Severity
+		Message  string
+	}{
+		{
+			"Given a CNAME at the apex",
+			[]*DomainRecord{{Type: CNameType, Name: Ptr, Data: "example.net"}},
+			SeverityError, "apex",
+		},
+		{
+			"Given a CNAME alongside a TXT record",
+			[]*DomainRecord{
+				{Type: CNameType, Name: "www", Data: Ptr},
+				{Type: TXTType, Name: "WWW.example.com.", Data: "conflict"},
+			},
+			SeverityError, "coexist with other records (TXT)",
+		},
+		{
+			"Given multiple CNAMEs with the same name",
+			[]*DomainRecord{
+				{Type: CNameType, Name: "www", Data: Ptr},
+				{Type: CNameType, Name: "www", Data: "example.net"},
+			},
+			SeverityError, "found 2 CNAME records",
+		},
+		{
+			"Given an MX target that is a CNAME",
+			[]*DomainRecord{
+				{Type: CNameType, Name: "mail", Data: "mail.example.net"},
+				{Type: MXType, Name: Ptr, Data: "mail.example.com"},
+			},
+			SeverityWarning, "target mail.example.com is a CNAME",
+		},
+		{
+			"Given an NS target that is a CNAME",
+			[]*DomainRecord{
+				{Type: CNameType, Name: "ns1", Data: "ns1.example.net"},
+				{Type: NSType, Name: "sub", Data: "ns1.example.com."},
+			},
+			SeverityWarning, "NS records must point at a name with address records",
+		},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			issues := LintRecords("example.com", test.Records)
+			if len(issues) != 1 {
+				t.Fatalf("expected a single issue, got %v", issues)
+			}
+			if issues[0].Severity != test.Severity {
+				t.Errorf("expected a %s, got a %s", test.Severity, issues[0].Severity)
+			}
+			if !strings.Contains(issues[0].Error(), test.Message) {
+				t.Errorf("expected %q to contain %q", issues[0].Error(), test.Message)
+			}
+		})
+	}
+}
+
+func TestLintRecordsConsistent(t *testing.T) {
+	records := []*DomainRecord{
+		{Type: AType, Name: Ptr, Data: "127.0.0.1"},
+		{Type: NSType, Name: Ptr, Data: "ns1.domaincontrol.com"},
+		{Type: CNameType, Name: "www", Data: Ptr},
+		{Type: CNameType, Name: "mail", Data: "mail.example.net"},
+		{Type: MXType, Name: Ptr, Data: "mail.example.net"},
+		{Type: MXType, Name: Ptr, Data: Ptr},
+	}
+	if issues := LintRecords("example.com", records); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestLintErrors(t *testing.T) {
+	issues := LintRecords("example.com", []*DomainRecord{
+		{Type: CNameType, Name: Ptr, Data: "example.net"},
+		{Type: CNameType, Name: "mail", Data: "example.net"},
+		{Type: MXType, Name: Ptr, Data: "mail.example.com"},
+	})
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %v", issues)
+	}
+	if errs := LintErrors(issues); len(errs) != 1 || errs[0].Name != Ptr {
+		t.Errorf("expected the apex CNAME error, got %v", errs)
+	}
+}
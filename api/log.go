@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	loggerName = "godaddy"
+	redacted   = "REDACTED"
+)
+
+// sensitiveKeys enumerates the JSON attributes returned by the GoDaddy API
+// that contain customer contact information or secrets. Keys are matched
+// case-insensitively.
+var sensitiveKeys = map[string]struct{}{
+	"addressmailing": {},
+	"authcode":       {},
+	"email":          {},
+	"fax":            {},
+	"jobtitle":       {},
+	"namefirst":      {},
+	"namelast":       {},
+	"namemiddle":     {},
+	"organization":   {},
+	"phone":          {},
+}
+
+// NewLogger constructs a leveled logger for the named subsystem. The level
+// honors TF_LOG_PROVIDER and TF_LOG, defaulting to INFO.
+func NewLogger(subsystem string) hclog.Logger {
+	level := os.Getenv("TF_LOG_PROVIDER")
+	if level == "" {
+		level = os.Getenv("TF_LOG")
+	}
+
+	return hclog.New(&hclog.LoggerOptions{
+		Name:   loggerName,
+		Level:  hclog.LevelFromString(level),
+		Output: os.Stderr,
+	}).Named(subsystem)
+}
+
+// redactHeaders returns a copy of the supplied headers that is safe to log
+func redactHeaders(h http.Header) http.Header {
+	safe := h.Clone()
+	if safe.Get(headerAuthorization) != "" {
+		safe.Set(headerAuthorization, redacted)
+	}
+	return safe
+}
+
+// RedactBody masks contact details and secrets within a JSON payload. Non-JSON
+// payloads are returned as-is.
+func RedactBody(body []byte) string {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return string(body)
+	}
+
+	b, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, nested := range value {
+			if isSensitiveKey(k) {
+				value[k] = redacted
+				continue
+			}
+			value[k] = redactValue(nested)
+		}
+	case []interface{}:
+		for i, nested := range value {
+			value[i] = redactValue(nested)
+		}
+	}
+	return v
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasPrefix(key, "contact") {
+		return true
+	}
+	_, ok := sensitiveKeys[key]
+	return ok
+}
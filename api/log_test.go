@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := []byte(`{"domain":"example.com","authCode":"secret","contactAdmin":{"email":"jdoe@example.com"},"records":[{"name":"@","email":"x"}]}`)
+	out := RedactBody(body)
+
+	for _, leaked := range []string{"secret", "jdoe@example.com", `"x"`} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("expected %q to be redacted: %s", leaked, out)
+		}
+	}
+	if !strings.Contains(out, "example.com") {
+		t.Errorf("expected non-sensitive data to be retained: %s", out)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set(headerAuthorization, "sso-key abc:123")
+	h.Set(headerAccept, mediaTypeJSON)
+
+	safe := redactHeaders(h)
+	if got := safe.Get(headerAuthorization); got != redacted {
+		t.Errorf("expected authorization header to be redacted, got %q", got)
+	}
+	if got := h.Get(headerAuthorization); got != "sso-key abc:123" {
+		t.Errorf("expected original headers to be untouched, got %q", got)
+	}
+	if got := safe.Get(headerAccept); got != mediaTypeJSON {
+		t.Errorf("expected accept header to be retained, got %q", got)
+	}
+}
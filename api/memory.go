@@ -0,0 +1,210 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryClient is a thread-safe, in-memory DNSClient. It applies the same
+// replace-by-type semantics as Client and is intended for tests and dry runs.
+type MemoryClient struct {
+	domains map[string]*memoryDomain
+	nextID  int64
+	sync.RWMutex
+}
+
+type memoryDomain struct {
+	customerID string
+	domain     Domain
+	records    []*DomainRecord
+}
+
+var _ DNSClient = (*MemoryClient)(nil)
+
+// NewMemoryClient constructs an empty in-memory DNSClient
+func NewMemoryClient() *MemoryClient {
+	return &MemoryClient{
+		domains: make(map[string]*memoryDomain),
+		nextID:  1,
+	}
+}
+
+// AddDomain registers a domain and its initial records for the customer. A
+// domain ID and status are assigned if not supplied.
+func (m *MemoryClient) AddDomain(customerID string, domain Domain, records ...*DomainRecord) {
+	m.Lock()
+	defer m.Unlock()
+
+	if domain.ID == 0 {
+		domain.ID = m.nextID
+	}
+	if domain.ID >= m.nextID {
+		m.nextID = domain.ID + 1
+	}
+	if domain.Status == "" {
+		domain.Status = StatusActive
+	}
+
+	m.domains[memoryKey(customerID, domain.Name)] = &memoryDomain{
+		customerID: customerID,
+		domain:     domain,
+		records:    copyRecords(records),
+	}
+}
+
+// GetDomains fetches the domains registered for the customer
+func (m *MemoryClient) GetDomains(customerID string) ([]Domain, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	domains := make([]Domain, 0)
+	for _, d := range m.domains {
+		if d.customerID == customerID {
+			domains = append(domains, d.domain)
+		}
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].Name < domains[j].Name
+	})
+	return domains, nil
+}
+
+// GetDomain fetches the details for the provided domain
+func (m *MemoryClient) GetDomain(customerID, domain string) (*Domain, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	d, err := m.lookup(customerID, domain)
+	if err != nil {
+		return nil, err
+	}
+	result := d.domain
+	return &result, nil
+}
+
+// GetDomainRecords fetches all existing records for the provided domain
+func (m *MemoryClient) GetDomainRecords(customerID, domain string) ([]*DomainRecord, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	d, err := m.lookup(customerID, domain)
+	if err != nil {
+		return nil, err
+	}
+	return copyRecords(d.records), nil
+}
+
+// UpdateDomainRecords replaces all existing records of each supported type
+func (m *MemoryClient) UpdateDomainRecords(customerID, domain string, records []*DomainRecord) error {
+	m.Lock()
+	defer m.Unlock()
+
+	d, err := m.lookup(customerID, domain)
+	if err != nil {
+		return err
+	}
+	if records, err = NormalizeRecords(domain, records); err != nil {
+		return err
+	}
+
+	for _, t := range ReplacedTypes(records) {
+		typeRecords := domainRecordsOfType(t, records)
+		d.records = append(withoutType(t, d.records), copyRecords(typeRecords)...)
+	}
+	return nil
+}
+
+// SetDomainRecords replaces all existing records of the type and name
+func (m *MemoryClient) SetDomainRecords(customerID, domain, t, name string, records []*DomainRecord) error {
+	m.Lock()
+	defer m.Unlock()
+
+	d, err := m.lookup(customerID, domain)
+	if err != nil {
+		return err
+	}
+	if name, err = NormalizeName(domain, name); err != nil {
+		return err
+	}
+
+	replacements := copyRecords(records)
+	for _, record := range replacements {
+		record.Type = t
+		record.Name = name
+	}
+	d.records = append(withoutTypeAndName(t, name, d.records), replacements...)
+	return nil
+}
+
+// DeleteDomainRecords removes all existing records of the type and name
+func (m *MemoryClient) DeleteDomainRecords(customerID, domain, t, name string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	d, err := m.lookup(customerID, domain)
+	if err != nil {
+		return err
+	}
+	if name, err = NormalizeName(domain, name); err != nil {
+		return err
+	}
+
+	remaining := withoutTypeAndName(t, name, d.records)
+	if len(remaining) == len(d.records) {
+		return fmt.Errorf("[%d:NOT_FOUND] no %s records found for %s", http.StatusNotFound, t, name)
+	}
+	d.records = remaining
+	return nil
+}
+
+func (m *MemoryClient) lookup(customerID, domain string) (*memoryDomain, error) {
+	d, ok := m.domains[memoryKey(customerID, domain)]
+	if !ok {
+		return nil, fmt.Errorf("[%d:NOT_FOUND] domain not found: %s", http.StatusNotFound, domain)
+	}
+	return d, nil
+}
+
+func memoryKey(customerID, domain string) string {
+	domain = strings.ToLower(domain)
+	if ascii, err := ToASCII(domain); err == nil {
+		domain = ascii
+	}
+	return customerID + "/" + domain
+}
+
+func withoutType(t string, records []*DomainRecord) []*DomainRecord {
+	result := make([]*DomainRecord, 0, len(records))
+	for _, record := range records {
+		if !strings.EqualFold(record.Type, t) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func withoutTypeAndName(t, name string, records []*DomainRecord) []*DomainRecord {
+	result := make([]*DomainRecord, 0, len(records))
+	for _, record := range records {
+		if !strings.EqualFold(record.Type, t) || !strings.EqualFold(record.Name, name) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func copyRecords(records []*DomainRecord) []*DomainRecord {
+	result := make([]*DomainRecord, len(records))
+	for i, record := range records {
+		c := *record
+		if record.Port != nil {
+			port := *record.Port
+			c.Port = &port
+		}
+		result[i] = &c
+	}
+	return result
+}
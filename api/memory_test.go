@@ -0,0 +1,38 @@
+package api
+
+import "testing"
+
+func TestMemoryClientUpdateDomainRecords(t *testing.T) {
+	client := NewMemoryClient()
+	client.AddDomain("", Domain{Name: "example.com"},
+		&DomainRecord{Type: NSType, Name: Ptr, Data: "ns1.domaincontrol.com", TTL: DefaultTTL},
+		&DomainRecord{Type: TXTType, Name: Ptr, Data: "stale", TTL: DefaultTTL},
+	)
+
+	a, _ := NewARecord("127.0.0.1")
+	if err := client.UpdateDomainRecords("", "example.com", []*DomainRecord{a}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := client.GetDomainRecords("", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected the NS and A records to remain, got %d records", len(records))
+	}
+	for _, rec := range records {
+		if rec.Type == TXTType {
+			t.Errorf("expected TXT records to be replaced: %+v", rec)
+		}
+	}
+
+	records[0].Data = "mutated"
+	if again, _ := client.GetDomainRecords("", "example.com"); again[0].Data == "mutated" {
+		t.Error("expected records to be copied")
+	}
+
+	if _, err := client.GetDomain("", "unknown.com"); err == nil {
+		t.Error("expected an error for an unknown domain")
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeName converts a record name into the form GoDaddy stores: "@" for
+// the apex and a lower case A-label name relative to the domain otherwise.
+// Names may be supplied relative to the domain, or fully qualified with or
+// without a trailing dot, in Unicode or A-label form. Fully qualified names
+// outside of the domain are rejected.
+func NormalizeName(domain, name string) (string, error) {
+	domain, err := ToASCII(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), ".")))
+	if err != nil {
+		return "", err
+	}
+	name, err = ToASCII(strings.ToLower(strings.TrimSpace(name)))
+	if err != nil {
+		return "", err
+	}
+
+	absolute := strings.HasSuffix(name, ".")
+	name = strings.TrimSuffix(name, ".")
+
+	switch {
+	case absolute && name == "":
+		return "", fmt.Errorf("name %q is outside of domain %s", ".", domain)
+	case name == "" || name == Ptr || name == domain:
+		return Ptr, nil
+	case domain != "" && strings.HasSuffix(name, "."+domain):
+		return strings.TrimSuffix(name, "."+domain), nil
+	case absolute:
+		return "", fmt.Errorf("name %q is outside of domain %s", name+".", domain)
+	}
+	return name, nil
+}
+
+// EquivalentName is a predicate that compares record names within a domain,
+// ignoring differences in case and qualification
+func EquivalentName(domain, a, b string) bool {
+	x, errX := NormalizeName(domain, a)
+	y, errY := NormalizeName(domain, b)
+	return errX == nil && errY == nil && x == y
+}
+
+// NormalizeRecords returns copies of the records with normalized names
+func NormalizeRecords(domain string, records []*DomainRecord) ([]*DomainRecord, error) {
+	normalized := make([]*DomainRecord, len(records))
+	for i, rec := range records {
+		name, err := NormalizeName(domain, rec.Name)
+		if err != nil {
+			return nil, err
+		}
+		copied := *rec
+		copied.Name = name
+		normalized[i] = &copied
+	}
+	return normalized, nil
+}
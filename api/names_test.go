@@ -0,0 +1,53 @@
+package api
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Record   string
+		Expected string
+		Negative bool
+	}{
+		{"Given a relative name", "www", "www", false},
+		{"Given an empty name", "", Ptr, false},
+		{"Given the apex", Ptr, Ptr, false},
+		{"Given the domain", "example.com", Ptr, false},
+		{"Given the domain with a trailing dot", "Example.COM.", Ptr, false},
+		{"Given a fully qualified name", "www.example.com", "www", false},
+		{"Given a fully qualified name with a trailing dot", "WWW.example.com.", "www", false},
+		{"Given a nested name", "a.b.example.com.", "a.b", false},
+		{"Given a wildcard", "*.example.com", "*", false},
+		{"Given a name outside of the domain", "www.example.net.", "", true},
+		{"Given the root", ".", "", true},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			name, err := NormalizeName("example.com", test.Record)
+			if test.Negative {
+				if err == nil {
+					t.Errorf("expected %q to be rejected, got %q", test.Record, name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != test.Expected {
+				t.Errorf("expected %q, got %q", test.Expected, name)
+			}
+		})
+	}
+}
+
+func TestEquivalentName(t *testing.T) {
+	if !EquivalentName("example.com", "www.example.com.", "www") {
+		t.Error("expected a fully qualified name to match its relative name")
+	}
+	if !EquivalentName("example.com", "", Ptr) {
+		t.Error("expected an empty name to match the apex")
+	}
+	if EquivalentName("example.com", "www", "mail") {
+		t.Error("expected different names to differ")
+	}
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// DefaultNameserverPort is the port on which nameservers are queried
+	DefaultNameserverPort = 53
+	// DefaultResolverTimeout bounds each DNS query
+	DefaultResolverTimeout = 5 * time.Second
+
+	maxUDPMessageSize = 1232
+)
+
+// Resolver verifies that nameservers are able to serve a zone before it is
+// delegated to them
+type Resolver struct {
+	// Addr is the host:port of the recursive resolver used to look up the
+	// addresses of nameservers. The system resolver is used when empty.
+	Addr string
+	// Port is the port on which nameservers are queried, which defaults to
+	// DefaultNameserverPort
+	Port int
+	// Timeout bounds each query, defaulting to DefaultResolverTimeout
+	Timeout time.Duration
+}
+
+// ApexNameservers returns the sorted, normalized targets of the NS records
+// at the apex of the domain
+func ApexNameservers(domain string, records []*DomainRecord) []string {
+	var nameservers []string
+	for _, rec := range CanonicalRecords(domain, records) {
+		if rec.Type == NSType && rec.Name == Ptr {
+			nameservers = append(nameservers, strings.ToLower(strings.TrimSuffix(rec.Data, ".")))
+		}
+	}
+	sort.Strings(nameservers)
+	return nameservers
+}
+
+// CheckNameservers ensures that each nameserver resolves and answers
+// authoritatively for the domain
+func (r *Resolver) CheckNameservers(ctx context.Context, domain string, nameservers []string) error {
+	zone, err := fqdn(domain)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, ns := range nameservers {
+		if err := r.checkNameserver(ctx, zone, ns); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("nameserver check failed for %s:\n  - %s", domain, strings.Join(errs, "\n  - "))
+	}
+	return nil
+}
+
+func (r *Resolver) checkNameserver(ctx context.Context, zone dnsmessage.Name, ns string) error {
+	addrs, err := r.lookupHost(ctx, ns)
+	if err != nil {
+		return fmt.Errorf("%s does not resolve: %s", ns, err)
+	}
+
+	port := r.Port
+	if port == 0 {
+		port = DefaultNameserverPort
+	}
+
+	var last error
+	for _, addr := range addrs {
+		server := net.JoinHostPort(addr.String(), strconv.Itoa(port))
+		header, answers, err := r.query(ctx, server, zone, dnsmessage.TypeSOA, false)
+		switch {
+		case err != nil:
+			last = err
+		case header.RCode != dnsmessage.RCodeSuccess:
+			last = fmt.Errorf("answered %s", header.RCode)
+		case !header.Authoritative:
+			last = errors.New("is not authoritative")
+		case !hasSOA(answers, zone):
+			last = errors.New("returned no SOA record")
+		default:
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not serve %s: %s", ns, strings.TrimSuffix(zone.String(), "."), last)
+}
+
+// lookupHost resolves the IPv4 and IPv6 addresses of a host with the
+// configured resolver, falling back to the system resolver
+func (r *Resolver) lookupHost(ctx context.Context, host string) ([]net.IP, error) {
+	name, err := fqdn(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Addr == "" {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, name.String())
+		if err != nil {
+			return nil, err
+		}
+		ips := make([]net.IP, len(addrs))
+		for i, addr := range addrs {
+			ips[i] = addr.IP
+		}
+		return ips, nil
+	}
+
+	var ips []net.IP
+	for _, t := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		header, answers, err := r.query(ctx, r.Addr, name, t, true)
+		if err != nil {
+			return nil, err
+		}
+		if header.RCode != dnsmessage.RCodeSuccess {
+			return nil, fmt.Errorf("resolver answered %s", header.RCode)
+		}
+
+		for _, answer := range answers {
+			switch body := answer.Body.(type) {
+			case *dnsmessage.AResource:
+				ips = append(ips, net.IP(body.A[:]))
+			case *dnsmessage.AAAAResource:
+				ips = append(ips, net.IP(body.AAAA[:]))
+			}
+		}
+	}
+	if len(ips) == 0 {
+		return nil, errors.New("no addresses found")
+	}
+	return ips, nil
+}
+
+// query sends a single question to a DNS server over UDP, retrying over TCP
+// when the response is truncated
+func (r *Resolver) query(ctx context.Context, server string, name dnsmessage.Name, t dnsmessage.Type, recursive bool) (dnsmessage.Header, []dnsmessage.Resource, error) {
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = DefaultResolverTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	id := uint16(rand.Intn(1 << 16))
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: recursive},
+		Questions: []dnsmessage.Question{{Name: name, Type: t, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := msg.Pack()
+	if err != nil {
+		return dnsmessage.Header{}, nil, err
+	}
+
+	reply, err := exchange(ctx, "udp", server, id, packed)
+	if err == nil && reply.Truncated {
+		reply, err = exchange(ctx, "tcp", server, id, packed)
+		if err == nil && reply.Truncated {
+			err = fmt.Errorf("truncated response from %s", server)
+		}
+	}
+	if err != nil {
+		return dnsmessage.Header{}, nil, err
+	}
+	return reply.Header, reply.Answers, nil
+}
+
+// exchange sends a packed query over the network and waits for the matching
+// response. Messages sent over TCP are prefixed with their length.
+func exchange(ctx context.Context, network, server string, id uint16, packed []byte) (*dnsmessage.Message, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	stream := network == "tcp"
+	if stream {
+		packed = append([]byte{byte(len(packed) >> 8), byte(len(packed))}, packed...)
+	}
+	if _, err := conn.Write(packed); err != nil {
+		return nil, err
+	}
+
+	for {
+		buf, err := readMessage(conn, stream)
+		if err != nil {
+			return nil, err
+		}
+
+		var reply dnsmessage.Message
+		if err := reply.Unpack(buf); err != nil || reply.ID != id || !reply.Response {
+			// ignore malformed or unrelated messages
+			continue
+		}
+		return &reply, nil
+	}
+}
+
+func readMessage(conn net.Conn, stream bool) ([]byte, error) {
+	if !stream {
+		buf := make([]byte, maxUDPMessageSize)
+		n, err := conn.Read(buf)
+		return buf[:n], err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, int(length[0])<<8|int(length[1]))
+	_, err := io.ReadFull(conn, buf)
+	return buf, err
+}
+
+func hasSOA(answers []dnsmessage.Resource, zone dnsmessage.Name) bool {
+	for _, answer := range answers {
+		if _, ok := answer.Body.(*dnsmessage.SOAResource); ok && strings.EqualFold(answer.Header.Name.String(), zone.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// fqdn converts a host name into a fully qualified DNS name
+func fqdn(host string) (dnsmessage.Name, error) {
+	ascii, err := ToASCII(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), ".")))
+	if err != nil {
+		return dnsmessage.Name{}, err
+	}
+	return dnsmessage.NewName(ascii + ".")
+}
@@ -0,0 +1,77 @@
+package api_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/n3integration/terraform-provider-godaddy/api/godaddytest"
+)
+
+func TestResolverCheckNameservers(t *testing.T) {
+	server := godaddytest.NewDNSServer()
+	defer server.Close()
+	server.AddHost("ns1.example.net")
+	server.AddHost("ns2.example.net")
+	server.AddHost("ns6.example.net", net.IPv6loopback)
+	server.AddZone("example.com")
+
+	resolver := &api.Resolver{Addr: server.Addr, Port: server.Port(), Timeout: time.Second}
+
+	var criteria = []struct {
+		Name        string
+		Domain      string
+		Nameservers []string
+		Error       string
+	}{
+		{"Given authoritative nameservers", "example.com", []string{"ns1.example.net", "NS2.example.net."}, ""},
+		{"Given a nameserver that does not resolve", "example.com", []string{"ns1.example.net", "ns3.example.net"}, "ns3.example.net does not resolve"},
+		{"Given a zone that is not served", "example.org", []string{"ns1.example.net"}, "ns1.example.net does not serve example.org: answered RCodeRefused"},
+		// the server only listens on IPv4, so the nameserver resolves but does not answer
+		{"Given an IPv6-only nameserver", "example.com", []string{"ns6.example.net"}, "ns6.example.net does not serve example.com"},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			err := resolver.CheckNameservers(context.Background(), test.Domain, test.Nameservers)
+			if test.Error == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.Error) {
+				t.Errorf("expected an error containing %q, got %v", test.Error, err)
+			}
+		})
+	}
+}
+
+func TestResolverCheckNameserversTruncated(t *testing.T) {
+	server := godaddytest.NewDNSServer()
+	defer server.Close()
+	server.AddHost("ns1.example.net")
+	server.AddZone("example.com")
+	server.SetTruncate(true)
+
+	resolver := &api.Resolver{Addr: server.Addr, Port: server.Port(), Timeout: time.Second}
+	if err := resolver.CheckNameservers(context.Background(), "example.com", []string{"ns1.example.net"}); err != nil {
+		t.Fatalf("expected truncated responses to be retried over TCP, got %s", err)
+	}
+}
+
+func TestApexNameservers(t *testing.T) {
+	records := []*api.DomainRecord{
+		{Type: api.NSType, Name: "example.com.", Data: "NS2.domaincontrol.com."},
+		{Type: "ns", Name: api.Ptr, Data: "ns1.domaincontrol.com"},
+		{Type: api.NSType, Name: "sub", Data: "ns1.example.net"},
+		{Type: api.AType, Name: api.Ptr, Data: "127.0.0.1"},
+	}
+
+	expected := "ns1.domaincontrol.com,ns2.domaincontrol.com"
+	if actual := strings.Join(api.ApexNameservers("example.com", records), ","); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
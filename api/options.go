@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// ClientOpt provides support for setting optional client parameters
+type ClientOpt func(*Client) error
+
+// WithBodyLogging enables logging of request and response bodies at the
+// DEBUG level. Contact details are redacted, but zone contents are not.
+func WithBodyLogging(enabled bool) ClientOpt {
+	return func(c *Client) error {
+		c.logBodies = enabled
+		return nil
+	}
+}
+
+// WithHTTPClient uses the supplied http.Client for API calls. Its transport is
+// still wrapped by the client's rate limiter and retry policy.
+func WithHTTPClient(hc *http.Client) ClientOpt {
+	return func(c *Client) error {
+		if hc == nil {
+			return errors.New("http client must not be nil")
+		}
+		c.httpClient = hc
+		return nil
+	}
+}
+
+// WithTransport replaces the underlying http.RoundTripper, which defaults to
+// an http.Transport with a 10s dial timeout.
+func WithTransport(rt http.RoundTripper) ClientOpt {
+	return func(c *Client) error {
+		if rt == nil {
+			return errors.New("transport must not be nil")
+		}
+		c.transport = rt
+		c.transportSet = true
+		return nil
+	}
+}
+
+// WithTimeout sets the overall time limit for a single API call, including
+// any retries. A zero value disables the timeout.
+func WithTimeout(timeout time.Duration) ClientOpt {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return errors.New("timeout must be a positive value")
+		}
+		c.timeout = timeout
+		c.timeoutSet = true
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request
+func WithUserAgent(userAgent string) ClientOpt {
+	return func(c *Client) error {
+		c.userAgent = strings.TrimSpace(userAgent)
+		return nil
+	}
+}
+
+// WithLogger replaces the default logger. A nil logger disables logging.
+func WithLogger(logger hclog.Logger) ClientOpt {
+	return func(c *Client) error {
+		if logger == nil {
+			logger = hclog.NewNullLogger()
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithRateLimiter replaces the default limiter of one request per second. A
+// nil limiter disables throttling.
+func WithRateLimiter(limiter RateLimiter) ClientOpt {
+	return func(c *Client) error {
+		c.limiter = limiter
+		return nil
+	}
+}
+
+// WithRetryPolicy enables retries of throttled or failed requests
+func WithRetryPolicy(policy RetryPolicy) ClientOpt {
+	return func(c *Client) error {
+		if policy.MaxRetries < 0 {
+			return errors.New("max retries must be a positive value")
+		}
+		if policy.MaxBackoff > 0 && policy.MaxBackoff < policy.MinBackoff {
+			return errors.New("max backoff must not be less than min backoff")
+		}
+		c.retry = policy
+		return nil
+	}
+}
+
+// WithSnapshots stores the full record set of a domain in dir, using the
+// named format (SnapshotJSON or SnapshotZone), before UpdateDomainRecords,
+// SetDomainRecords or DeleteDomainRecords changes any records. Snapshots may
+// be restored with RestoreSnapshot.
+func WithSnapshots(dir, format string) ClientOpt {
+	return func(c *Client) error {
+		if strings.TrimSpace(dir) == "" {
+			return errors.New("snapshot directory must not be empty")
+		}
+		if format == "" {
+			format = SnapshotJSON
+		}
+		if _, err := lookupSnapshotFormat(format); err != nil {
+			return err
+		}
+		c.snapshotDir = dir
+		c.snapshotFormat = format
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientOptions(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":"TOO_MANY_REQUESTS","message":"slow down"}`))
+			return
+		}
+		if got := r.Header.Get(headerUserAgent); got != "godaddy-test/1.0" {
+			t.Errorf("unexpected user agent: %q", got)
+		}
+		w.Write([]byte(`{"domainId":1,"domain":"example.com","status":"ACTIVE"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "key", "secret",
+		WithUserAgent("godaddy-test/1.0"),
+		WithTimeout(5*time.Second),
+		WithRateLimiter(nil),
+		WithLogger(nil),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domain, err := client.GetDomain("", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain.ID != 1 {
+		t.Errorf("unexpected domain: %+v", domain)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestInvalidClientOptions(t *testing.T) {
+	var criteria = []struct {
+		Name string
+		Opt  ClientOpt
+	}{
+		{"Given a nil http client", WithHTTPClient(nil)},
+		{"Given a nil transport", WithTransport(nil)},
+		{"Given a negative timeout", WithTimeout(-time.Second)},
+		{"Given a negative retry count", WithRetryPolicy(RetryPolicy{MaxRetries: -1})},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			if _, err := NewClient("https://api.godaddy.com", "key", "secret", test.Opt); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(20 * time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected requests to be throttled, took %s", elapsed)
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Safeguards limit the damage that a single update may do to a domain, such
+// as a typo that empties a zone. A zero limit is not enforced.
+type Safeguards struct {
+	// MaxRecordsDeleted is the number of records that may be deleted
+	MaxRecordsDeleted int
+	// MaxPercentChanged is the share of the existing records that may be
+	// changed or deleted
+	MaxPercentChanged int
+	// ProtectedRecords are patterns of the form "TYPE" or "TYPE NAME" (e.g.
+	// "MX" or "NS @") identifying records that may not be removed. The type
+	// may be "*" and the name may contain glob wildcards.
+	ProtectedRecords []string
+}
+
+// SafeguardError describes the safeguards tripped by an update
+type SafeguardError struct {
+	Domain     string
+	Violations []string
+	Diff       RecordDiff
+}
+
+func (e *SafeguardError) Error() string {
+	return fmt.Sprintf("safeguards prevented changes to %s: %s", e.Domain, strings.Join(e.Violations, "; "))
+}
+
+// ValidateProtectedPattern ensures that a protected records pattern is well
+// formed
+func ValidateProtectedPattern(pattern string) error {
+	fields := strings.Fields(pattern)
+	if len(fields) == 0 || len(fields) > 2 {
+		return fmt.Errorf("invalid protected records pattern %q: expected TYPE or TYPE NAME", pattern)
+	}
+	if t := strings.ToUpper(fields[0]); t != "*" && !IsSupportedType(t) {
+		return fmt.Errorf("invalid protected records pattern %q: unsupported record type %s", pattern, fields[0])
+	}
+	if len(fields) == 2 {
+		if _, err := path.Match(fields[1], ""); err != nil {
+			return fmt.Errorf("invalid protected records pattern %q: %s", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Check evaluates the safeguards against the changes that
+// UpdateDomainRecords would make when replacing current with desired,
+// returning a *SafeguardError describing every violation
+func (s Safeguards) Check(domain string, current, desired []*DomainRecord) error {
+	return s.CheckChange(domain, ReplacedRecords(current, desired), ReplacedRecords(desired, desired))
+}
+
+// CheckChange evaluates the safeguards against a change of the records of a
+// domain from before to after, such as the removal of records on destroy.
+// The SOA record is managed by GoDaddy and is ignored.
+func (s Safeguards) CheckChange(domain string, before, after []*DomainRecord) error {
+	existing := CanonicalRecords(domain, withoutSOA(before))
+	replacement := CanonicalRecords(domain, withoutSOA(after))
+	diff := DiffRecords(existing, replacement)
+
+	var violations []string
+	if deleted := countDeleted(diff); s.MaxRecordsDeleted > 0 && deleted > s.MaxRecordsDeleted {
+		violations = append(violations, fmt.Sprintf("%d records would be deleted, exceeding the limit of %d", deleted, s.MaxRecordsDeleted))
+	}
+	if s.MaxPercentChanged > 0 && len(existing) > 0 {
+		changed := len(diff.Removed)
+		if percent := changed * 100 / len(existing); percent > s.MaxPercentChanged {
+			violations = append(violations, fmt.Sprintf("%d%% of the existing records (%d of %d) would be changed or deleted, exceeding the limit of %d%%",
+				percent, changed, len(existing), s.MaxPercentChanged))
+		}
+	}
+	for _, pattern := range s.ProtectedRecords {
+		for _, group := range removedGroups(existing, replacement) {
+			if matchesProtected(pattern, group.t, group.name) {
+				violations = append(violations, fmt.Sprintf("protected %s records at %s would be removed (%q)", group.t, group.name, pattern))
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &SafeguardError{Domain: domain, Violations: violations, Diff: diff}
+}
+
+// IsSafeguardError is a predicate for errors caused by tripped safeguards
+func IsSafeguardError(err error) bool {
+	var target *SafeguardError
+	return errors.As(err, &target)
+}
+
+type recordGroup struct {
+	t    string
+	name string
+}
+
+// countDeleted counts the removed records that are not replaced by an added
+// record of the same type and name
+func countDeleted(diff RecordDiff) int {
+	balance := make(map[recordGroup]int)
+	for _, rec := range diff.Removed {
+		balance[recordGroup{rec.Type, rec.Name}]++
+	}
+	for _, rec := range diff.Added {
+		balance[recordGroup{rec.Type, rec.Name}]--
+	}
+
+	deleted := 0
+	for _, n := range balance {
+		if n > 0 {
+			deleted += n
+		}
+	}
+	return deleted
+}
+
+// removedGroups returns the type and name pairs that exist, but that no
+// desired record retains, in their order of appearance
+func removedGroups(existing, desired []*DomainRecord) []recordGroup {
+	retained := make(map[recordGroup]bool)
+	for _, rec := range desired {
+		retained[recordGroup{rec.Type, rec.Name}] = true
+	}
+
+	var groups []recordGroup
+	seen := make(map[recordGroup]bool)
+	for _, rec := range existing {
+		g := recordGroup{rec.Type, rec.Name}
+		if retained[g] || seen[g] {
+			continue
+		}
+		seen[g] = true
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+func matchesProtected(pattern, t, name string) bool {
+	fields := strings.Fields(pattern)
+	if len(fields) == 0 || (fields[0] != "*" && !strings.EqualFold(fields[0], t)) {
+		return false
+	}
+	if len(fields) == 1 {
+		return true
+	}
+	ok, err := path.Match(strings.ToLower(fields[1]), name)
+	return err == nil && ok
+}
+
+func withoutSOA(records []*DomainRecord) []*DomainRecord {
+	filtered := make([]*DomainRecord, 0, len(records))
+	for _, rec := range records {
+		if !strings.EqualFold(rec.Type, SOAType) {
+			filtered = append(filtered, rec)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSafeguardsCheck(t *testing.T) {
+	current := []*DomainRecord{
+		{Type: AType, Name: Ptr, Data: "10.0.0.1", TTL: DefaultTTL},
+		{Type: AType, Name: "api", Data: "10.0.0.2", TTL: DefaultTTL},
+		{Type: CNameType, Name: "www", Data: Ptr, TTL: DefaultTTL},
+		{Type: MXType, Name: Ptr, Data: "mail.example.net", TTL: DefaultTTL, Priority: 10},
+		{Type: NSType, Name: Ptr, Data: "ns1.domaincontrol.com", TTL: DefaultTTL},
+		{Type: NSType, Name: Ptr, Data: "ns2.domaincontrol.com", TTL: DefaultTTL},
+	}
+
+	var criteria = []struct {
+		Name       string
+		Safeguards Safeguards
+		Desired    []*DomainRecord
+		Violations []string
+	}{
+		{
+			"Given more deletions than permitted",
+			Safeguards{MaxRecordsDeleted: 2},
+			[]*DomainRecord{{Type: AType, Name: Ptr, Data: "10.0.0.1", TTL: DefaultTTL}},
+			[]string{"3 records would be deleted, exceeding the limit of 2"},
+		},
+		{
+			"Given deletions within the limit",
+			Safeguards{MaxRecordsDeleted: 3},
+			[]*DomainRecord{{Type: AType, Name: Ptr, Data: "10.0.0.1", TTL: DefaultTTL}},
+			nil,
+		},
+		{
+			"Given modified records",
+			Safeguards{MaxRecordsDeleted: 1},
+			[]*DomainRecord{
+				{Type: AType, Name: Ptr, Data: "10.0.0.3", TTL: DefaultTTL},
+				{Type: AType, Name: "api", Data: "10.0.0.4", TTL: DefaultTTL},
+				{Type: CNameType, Name: "www", Data: Ptr, TTL: DefaultTTL},
+				{Type: MXType, Name: Ptr, Data: "mail.example.net", TTL: DefaultTTL, Priority: 20},
+			},
+			nil,
+		},
+		{
+			"Given more changes than permitted",
+			Safeguards{MaxPercentChanged: 50},
+			[]*DomainRecord{
+				{Type: AType, Name: Ptr, Data: "10.0.0.3", TTL: DefaultTTL},
+				{Type: AType, Name: "api", Data: "10.0.0.4", TTL: DefaultTTL},
+				{Type: CNameType, Name: "www", Data: Ptr, TTL: DefaultTTL},
+			},
+			[]string{"75% of the existing records (3 of 4) would be changed or deleted, exceeding the limit of 50%"},
+		},
+		{
+			"Given a protected record type",
+			Safeguards{ProtectedRecords: []string{"mx"}},
+			[]*DomainRecord{{Type: AType, Name: Ptr, Data: "10.0.0.1", TTL: DefaultTTL}},
+			[]string{`protected MX records at @ would be removed ("mx")`},
+		},
+		{
+			"Given a protected record name",
+			Safeguards{ProtectedRecords: []string{"* w*", "A @"}},
+			[]*DomainRecord{{Type: AType, Name: Ptr, Data: "10.0.0.1", TTL: DefaultTTL}},
+			[]string{`protected CNAME records at www would be removed ("* w*")`},
+		},
+		{
+			"Given a protected record that is modified",
+			Safeguards{ProtectedRecords: []string{"MX @"}},
+			[]*DomainRecord{{Type: MXType, Name: Ptr, Data: "mx.example.net", TTL: DefaultTTL}},
+			nil,
+		},
+		{
+			"Given the apex nameservers, which are not replaced when absent",
+			Safeguards{ProtectedRecords: []string{"NS @"}},
+			[]*DomainRecord{{Type: AType, Name: Ptr, Data: "10.0.0.1", TTL: DefaultTTL}},
+			nil,
+		},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Safeguards.Check("example.com", current, test.Desired)
+			if len(test.Violations) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if !IsSafeguardError(err) {
+				t.Fatalf("expected a safeguard error, got %v", err)
+			}
+			violations := err.(*SafeguardError).Violations
+			if strings.Join(violations, "\n") != strings.Join(test.Violations, "\n") {
+				t.Errorf("expected violations %q, got %q", test.Violations, violations)
+			}
+		})
+	}
+}
+
+func TestSafeguardsCheckChange(t *testing.T) {
+	before := []*DomainRecord{
+		{Type: SOAType, Name: Ptr, Data: "ns1.domaincontrol.com", TTL: DefaultTTL},
+		{Type: NSType, Name: Ptr, Data: "ns1.domaincontrol.com", TTL: DefaultTTL},
+		{Type: NSType, Name: "sub", Data: "ns1.example.net", TTL: DefaultTTL},
+		{Type: MXType, Name: Ptr, Data: "mail.example.net", TTL: DefaultTTL},
+	}
+	after := before[:2]
+
+	err := Safeguards{MaxRecordsDeleted: 1, ProtectedRecords: []string{"NS"}}.CheckChange("example.com", before, after)
+	if !IsSafeguardError(err) {
+		t.Fatalf("expected a safeguard error, got %v", err)
+	}
+	expected := []string{
+		"2 records would be deleted, exceeding the limit of 1",
+		`protected NS records at sub would be removed ("NS")`,
+	}
+	if violations := err.(*SafeguardError).Violations; strings.Join(violations, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected violations %q, got %q", expected, violations)
+	}
+}
+
+func TestValidateProtectedPattern(t *testing.T) {
+	for _, pattern := range []string{"MX", "ns @", "* _dmarc*", "TXT [a-z]*"} {
+		if err := ValidateProtectedPattern(pattern); err != nil {
+			t.Errorf("expected %q to be valid: %s", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", "BOGUS", "A @ extra", "A [a-"} {
+		if err := ValidateProtectedPattern(pattern); err == nil {
+			t.Errorf("expected %q to be invalid", pattern)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SnapshotJSON stores snapshots as JSON documents
+	SnapshotJSON = "json"
+	// SnapshotZone stores snapshots as zone files. The format is registered
+	// by the zonefile package, which must be imported to use it.
+	SnapshotZone = "zone"
+
+	snapshotTimeFormat = "20060102T150405.000000000Z"
+)
+
+// SnapshotFormat encodes and decodes the records of a snapshot
+type SnapshotFormat struct {
+	// Extension is the file name extension, without the leading dot
+	Extension string
+	// Marshal encodes the records of a domain
+	Marshal func(domain string, records []*DomainRecord) ([]byte, error)
+	// Unmarshal decodes the records of a domain
+	Unmarshal func(domain string, data []byte) ([]*DomainRecord, error)
+}
+
+var (
+	snapshotFormatsMu sync.RWMutex
+	snapshotFormats   = map[string]SnapshotFormat{
+		SnapshotJSON: {Extension: "json", Marshal: marshalJSONSnapshot, Unmarshal: unmarshalJSONSnapshot},
+	}
+)
+
+// RegisterSnapshotFormat makes a snapshot format available by name. Formats
+// that depend upon packages importing api (e.g. zonefile) register
+// themselves to avoid an import cycle.
+func RegisterSnapshotFormat(name string, format SnapshotFormat) {
+	snapshotFormatsMu.Lock()
+	defer snapshotFormatsMu.Unlock()
+	snapshotFormats[name] = format
+}
+
+func lookupSnapshotFormat(name string) (SnapshotFormat, error) {
+	snapshotFormatsMu.RLock()
+	defer snapshotFormatsMu.RUnlock()
+	if format, ok := snapshotFormats[name]; ok {
+		return format, nil
+	}
+
+	names := make([]string, 0, len(snapshotFormats))
+	for n := range snapshotFormats {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return SnapshotFormat{}, fmt.Errorf("unsupported snapshot format %q (expected one of %s)", name, strings.Join(names, ", "))
+}
+
+// formatForPath returns the snapshot format matching a file name extension
+func formatForPath(path string) (SnapshotFormat, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+
+	snapshotFormatsMu.RLock()
+	defer snapshotFormatsMu.RUnlock()
+	for _, format := range snapshotFormats {
+		if format.Extension == ext {
+			return format, nil
+		}
+	}
+	return SnapshotFormat{}, fmt.Errorf("unsupported snapshot file %s", path)
+}
+
+// WriteSnapshot stores the records of a domain in dir as a timestamped file
+// of the named format, returning its path
+func WriteSnapshot(dir, format, domain string, records []*DomainRecord) (string, error) {
+	f, err := lookupSnapshotFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := f.Marshal(domain, records)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%s.%s", strings.ToLower(domain), time.Now().UTC().Format(snapshotTimeFormat), f.Extension)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ReadSnapshot loads the records of a domain from a snapshot file. The
+// format is determined by the file name extension.
+func ReadSnapshot(path, domain string) ([]*DomainRecord, error) {
+	f, err := formatForPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return f.Unmarshal(domain, data)
+}
+
+// Snapshotter is implemented by clients that snapshot the records of a domain
+// before changing them
+type Snapshotter interface {
+	// SnapshotOnce snapshots the records of the domain and returns a client
+	// that changes them without taking further snapshots
+	SnapshotOnce(customerID, domain string) (DNSClient, error)
+}
+
+// SnapshotOnce snapshots the records of the domain once before a series of
+// changes, such as the deletions made on destroy, rather than once per
+// change. Clients that do not take snapshots are returned as-is.
+func SnapshotOnce(client DNSClient, customerID, domain string) (DNSClient, error) {
+	if s, ok := client.(Snapshotter); ok {
+		return s.SnapshotOnce(customerID, domain)
+	}
+	return client, nil
+}
+
+// RestoreSnapshot replaces the records of a domain with those of a snapshot
+// file, rolling back any changes made since it was taken. The SOA record is
+// managed by GoDaddy and is not restored.
+func RestoreSnapshot(client DNSClient, customerID, domain, path string) error {
+	records, err := ReadSnapshot(path, domain)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("snapshot %s contains no records", path)
+	}
+	return client.UpdateDomainRecords(customerID, domain, records)
+}
+
+// jsonSnapshot is the document stored by the JSON snapshot format
+type jsonSnapshot struct {
+	Domain  string          `json:"domain"`
+	Records []*DomainRecord `json:"records"`
+}
+
+func marshalJSONSnapshot(domain string, records []*DomainRecord) ([]byte, error) {
+	return json.MarshalIndent(jsonSnapshot{Domain: domain, Records: records}, "", "  ")
+}
+
+func unmarshalJSONSnapshot(domain string, data []byte) ([]*DomainRecord, error) {
+	var snapshot jsonSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Domain == "" {
+		return nil, errors.New("snapshot does not identify its domain")
+	}
+	if !EquivalentName(domain, snapshot.Domain, Ptr) {
+		return nil, fmt.Errorf("snapshot is for domain %s, not %s", snapshot.Domain, domain)
+	}
+	return snapshot.Records, nil
+}
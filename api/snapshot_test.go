@@ -0,0 +1,76 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotJSON(t *testing.T) {
+	dir := t.TempDir()
+	records := []*DomainRecord{
+		{Type: AType, Name: Ptr, Data: "127.0.0.1", TTL: DefaultTTL},
+		{Type: TXTType, Name: Ptr, Data: "v=spf1 -all", TTL: DefaultTTL},
+	}
+
+	path, err := WriteSnapshot(dir, SnapshotJSON, "Example.com", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "example.com-") || filepath.Ext(path) != ".json" {
+		t.Errorf("unexpected snapshot path %s", path)
+	}
+
+	restored, err := ReadSnapshot(path, "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 2 || restored[0].Data != "127.0.0.1" || restored[1].Data != "v=spf1 -all" {
+		t.Errorf("expected the records to round trip, got %v", restored)
+	}
+
+	if _, err := ReadSnapshot(path, "example.net"); err == nil {
+		t.Error("expected a snapshot of another domain to be rejected")
+	}
+}
+
+func TestSnapshotUnsupportedFormat(t *testing.T) {
+	if _, err := WriteSnapshot(t.TempDir(), "yaml", "example.com", nil); err == nil {
+		t.Error("expected an unsupported format to be rejected")
+	}
+
+	path := filepath.Join(t.TempDir(), "example.com.yaml")
+	if err := os.WriteFile(path, []byte("records: []"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSnapshot(path, "example.com"); err == nil {
+		t.Error("expected an unsupported snapshot file to be rejected")
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	client := NewMemoryClient()
+	client.AddDomain("", Domain{Name: "example.com"},
+		&DomainRecord{Type: AType, Name: "www", Data: "10.0.0.1", TTL: DefaultTTL},
+	)
+
+	records, _ := client.GetDomainRecords("", "example.com")
+	path, err := WriteSnapshot(t.TempDir(), SnapshotJSON, "example.com", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := NewDomainRecord("api", AType, "10.0.0.2", DefaultTTL)
+	if err := client.UpdateDomainRecords("", "example.com", []*DomainRecord{a}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreSnapshot(client, "", "example.com", path); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, _ := client.GetDomainRecords("", "example.com")
+	if len(restored) != 1 || restored[0].Name != "www" {
+		t.Errorf("expected the snapshot to be restored, got %v", restored)
+	}
+}
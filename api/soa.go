@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const soaFields = 7
+
+// SOAData is the parsed form of SOA record data (RFC 1035 section 3.3.13)
+type SOAData struct {
+	// MName is the primary nameserver for the zone
+	MName string
+	// RName is the mailbox of the person responsible for the zone, with the
+	// "@" replaced by a "."
+	RName   string
+	Serial  uint32
+	Refresh int
+	Retry   int
+	Expire  int
+	Minimum int
+}
+
+// ParseSOA parses and validates SOA record data in its presentation format:
+// <mname> <rname> <serial> <refresh> <retry> <expire> <minimum>
+func ParseSOA(data string) (*SOAData, error) {
+	fields := strings.Fields(data)
+	if len(fields) != soaFields {
+		return nil, fmt.Errorf("SOA data must be of the form <mname> <rname> <serial> <refresh> <retry> <expire> <minimum>: %q", data)
+	}
+
+	soa := &SOAData{MName: fields[0], RName: fields[1]}
+	if err := ValidateHostname(soa.MName); err != nil {
+		return nil, fmt.Errorf("SOA mname: %w", err)
+	}
+	if err := ValidateHostname(soa.RName); err != nil {
+		return nil, fmt.Errorf("SOA rname: %w", err)
+	}
+
+	serial, err := strconv.ParseUint(fields[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("SOA serial must be an unsigned 32 bit integer: %q", fields[2])
+	}
+	soa.Serial = uint32(serial)
+
+	timers := []*int{&soa.Refresh, &soa.Retry, &soa.Expire, &soa.Minimum}
+	for i, name := range []string{"refresh", "retry", "expire", "minimum"} {
+		v, err := strconv.ParseUint(fields[3+i], 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("SOA %s must be a positive number of seconds: %q", name, fields[3+i])
+		}
+		*timers[i] = int(v)
+	}
+	return soa, nil
+}
+
+// String renders the data in its presentation format
+func (s *SOAData) String() string {
+	return fmt.Sprintf("%s %s %d %d %d %d %d", s.MName, s.RName, s.Serial, s.Refresh, s.Retry, s.Expire, s.Minimum)
+}
+
+// ValidateSOATimer performs bounds checking on an SOA refresh, retry, expire
+// or minimum value
+func ValidateSOATimer(seconds int) error {
+	if seconds < 1 || seconds > 1<<31-1 {
+		return fmt.Errorf("SOA timers must be between 1..%d seconds", 1<<31-1)
+	}
+	return nil
+}
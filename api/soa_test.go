@@ -0,0 +1,39 @@
+package api
+
+import "testing"
+
+func TestParseSOA(t *testing.T) {
+	data := "ns51.domaincontrol.com. dns.jomax.net. 2023071801 28800 7200 604800 600"
+	soa, err := ParseSOA(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := SOAData{
+		MName:   "ns51.domaincontrol.com.",
+		RName:   "dns.jomax.net.",
+		Serial:  2023071801,
+		Refresh: 28800,
+		Retry:   7200,
+		Expire:  604800,
+		Minimum: 600,
+	}
+	if *soa != expected {
+		t.Errorf("expected %+v, got %+v", expected, *soa)
+	}
+	if got := soa.String(); got != data {
+		t.Errorf("expected %q, got %q", data, got)
+	}
+
+	for _, invalid := range []string{
+		"ns51.domaincontrol.com. dns.jomax.net. 2023071801 28800 7200 604800",
+		"ns51.domaincontrol.com. dns.jomax.net. 4294967296 28800 7200 604800 600",
+		"ns51.domaincontrol.com. dns.jomax.net. 2023071801 -1 7200 604800 600",
+		"ns51.domaincontrol.com. dns.jomax.net. 2023071801 1h 7200 604800 600",
+		"https://ns51 dns.jomax.net. 2023071801 28800 7200 604800 600",
+	} {
+		if _, err := ParseSOA(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
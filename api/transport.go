@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// RateLimiter throttles API calls. Wait blocks until the next request may be
+// sent or the context is done.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// intervalLimiter enforces a minimum interval between consecutive requests
+type intervalLimiter struct {
+	interval time.Duration
+	throttle time.Time
+	sync.Mutex
+}
+
+// NewRateLimiter constructs a RateLimiter that permits at most one request
+// per interval. It appears that the GoDaddy rate limit is 60 requests per
+// minute, which can be throttled and enforced at a maximum of one
+// request/second.
+func NewRateLimiter(interval time.Duration) RateLimiter {
+	return &intervalLimiter{
+		interval: interval,
+		throttle: time.Now().Add(-interval),
+	}
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.Lock()
+	now := time.Now()
+	delay := l.throttle.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	l.throttle = now.Add(delay + l.interval)
+	l.Unlock()
+
+	return sleep(ctx, delay)
+}
+
+// rateLimitedTransport throttles API calls to GoDaddy
+type rateLimitedTransport struct {
+	delegate http.RoundTripper
+	limiter  RateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.delegate.RoundTrip(req)
+}
+
+// RetryPolicy determines how throttled (429) and failed requests are retried.
+// Server errors and network failures are only retried for idempotent methods.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxRetries is the number of attempts made after the initial request
+	MaxRetries int
+	// MinBackoff is the delay before the first retry, doubling thereafter
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries up to three times, backing off from one second
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 1 * time.Second,
+	MaxBackoff: 30 * time.Second,
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MinBackoff << uint(attempt)
+	if p.MaxBackoff > 0 && (delay > p.MaxBackoff || delay <= 0) {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+func (p RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	idempotent := req.Method != http.MethodPost && req.Method != http.MethodPatch
+	if err != nil {
+		return idempotent && req.Context().Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// retryTransport replays requests according to a RetryPolicy
+type retryTransport struct {
+	delegate http.RoundTripper
+	policy   RetryPolicy
+	logger   hclog.Logger
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+			if req.Body != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := t.delegate.RoundTrip(attemptReq)
+		if attempt >= t.policy.MaxRetries || !t.policy.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		delay := t.policy.backoff(attempt)
+		args := []interface{}{"method", req.Method, "url", req.URL.String(), "attempt", attempt + 1}
+		if err != nil {
+			args = append(args, "error", err)
+		} else {
+			if after := retryAfter(resp); after > delay {
+				delay = after
+			}
+			args = append(args, "status", resp.StatusCode)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		t.logger.Warn("retrying request", append(args, "delay", delay.String())...)
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryAfter parses the Retry-After header expressed in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTXTStringLen is the maximum length of a single TXT <character-string>
+const MaxTXTStringLen = 255
+
+// ChunkTXT splits a TXT value into character-strings of at most 255 bytes,
+// without splitting multi-byte characters. Bytes that are not valid UTF-8,
+// such as those decoded from \DDD escapes, may be split anywhere.
+func ChunkTXT(value string) []string {
+	if value == "" {
+		return []string{""}
+	}
+
+	var chunks []string
+	for len(value) > MaxTXTStringLen {
+		n := MaxTXTStringLen
+		for i := n; i > n-utf8.UTFMax && i > 0; i-- {
+			if utf8.RuneStart(value[i]) {
+				n = i
+				break
+			}
+		}
+		chunks = append(chunks, value[:n])
+		value = value[n:]
+	}
+	return append(chunks, value)
+}
+
+// ParseTXT splits TXT data into its character-strings. Data made up entirely
+// of quoted strings (e.g. "v=DKIM1; k=rsa; " "p=MIGf...") is unquoted; any
+// other data is a single unquoted string.
+func ParseTXT(data string) []string {
+	trimmed := strings.TrimSpace(data)
+	if !strings.HasPrefix(trimmed, `"`) {
+		return []string{data}
+	}
+
+	var chunks []string
+	for rest := trimmed; rest != ""; rest = strings.TrimLeft(rest, " \t") {
+		chunk, n, ok := unquoteTXT(rest)
+		if !ok {
+			return []string{data}
+		}
+		chunks = append(chunks, chunk)
+		rest = rest[n:]
+	}
+	return chunks
+}
+
+// TXTValue returns the logical value of TXT data, with its character-strings
+// unquoted and joined
+func TXTValue(data string) string {
+	return strings.Join(ParseTXT(data), "")
+}
+
+// EncodeTXT renders a TXT value as record data. Values that fit within a
+// single character-string are left as-is; longer values are split into
+// quoted character-strings of at most 255 bytes.
+func EncodeTXT(value string) string {
+	if len(value) <= MaxTXTStringLen {
+		return value
+	}
+
+	chunks := ChunkTXT(value)
+	for i, chunk := range chunks {
+		chunks[i] = quoteTXT(chunk)
+	}
+	return strings.Join(chunks, " ")
+}
+
+// unquoteTXT decodes the quoted string at the start of s, returning it along
+// with the number of bytes consumed
+func unquoteTXT(s string) (string, int, bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return b.String(), i + 1, true
+		case '\\':
+			if i+1 == len(s) {
+				return "", 0, false
+			}
+			i++
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, false
+}
+
+func quoteTXT(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
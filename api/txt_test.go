@@ -0,0 +1,82 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChunkTXT(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Value    string
+		Expected []int
+	}{
+		{"Given an empty value", "", []int{0}},
+		{"Given a short value", "v=spf1 -all", []int{11}},
+		{"Given a value of exactly 255 bytes", strings.Repeat("a", 255), []int{255}},
+		{"Given a long value", strings.Repeat("a", 600), []int{255, 255, 90}},
+		{"Given multi-byte characters at the boundary", strings.Repeat("a", 254) + "é", []int{254, 2}},
+		{"Given invalid UTF-8", strings.Repeat("\x96", 300), []int{255, 45}},
+		{"Given invalid UTF-8 after a character", "é" + strings.Repeat("\x96", 300), []int{255, 47}},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			chunks := ChunkTXT(test.Value)
+			lengths := make([]int, len(chunks))
+			for i, chunk := range chunks {
+				lengths[i] = len(chunk)
+			}
+			if !reflect.DeepEqual(lengths, test.Expected) {
+				t.Errorf("expected chunk lengths %v, got %v", test.Expected, lengths)
+			}
+			if joined := strings.Join(chunks, ""); joined != test.Value {
+				t.Errorf("expected chunks to join to the original value")
+			}
+		})
+	}
+}
+
+func TestParseTXT(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Data     string
+		Expected []string
+	}{
+		{"Given unquoted data", "v=spf1 -all", []string{"v=spf1 -all"}},
+		{"Given a quoted string", `"v=spf1 -all"`, []string{"v=spf1 -all"}},
+		{"Given several quoted strings", `"v=DKIM1; " "p=MIGf"`, []string{"v=DKIM1; ", "p=MIGf"}},
+		{"Given escaped quotes", `"say \"hi\""`, []string{`say "hi"`}},
+		{"Given embedded quotes", `include:"x" -all`, []string{`include:"x" -all`}},
+		{"Given an unterminated quote", `"abc`, []string{`"abc`}},
+		{"Given trailing unquoted text", `"abc" def`, []string{`"abc" def`}},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := ParseTXT(test.Data); !reflect.DeepEqual(got, test.Expected) {
+				t.Errorf("expected %q, got %q", test.Expected, got)
+			}
+		})
+	}
+}
+
+func TestEncodeTXT(t *testing.T) {
+	if got := EncodeTXT("v=spf1 -all"); got != "v=spf1 -all" {
+		t.Errorf("expected short values to be left as-is, got %q", got)
+	}
+
+	value := `v=DKIM1; k=rsa; p="` + strings.Repeat("A", 400)
+	encoded := EncodeTXT(value)
+	if len(ParseTXT(encoded)) != 2 {
+		t.Errorf("expected long values to be split into 2 strings: %s", encoded)
+	}
+	if got := TXTValue(encoded); got != value {
+		t.Errorf("expected encoding to round-trip, got %q", got)
+	}
+	if !EquivalentData(TXTType, value, encoded) || !EquivalentData(TXTType, "a", `"a"`) {
+		t.Error("expected TXT data to ignore chunking and quoting")
+	}
+	if EquivalentData(TXTType, "a", "b") {
+		t.Error("expected different TXT values to differ")
+	}
+}
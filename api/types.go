@@ -218,13 +218,13 @@ func NewARecord(data string) (*DomainRecord, error) {
 	return NewDomainRecord(Ptr, AType, data, DefaultTTL)
 }
 
-// ValidateData performs bounds checking on a data element
+// ValidateData performs bounds checking on a data element, followed by the
+// syntax checks specific to the record type
 func ValidateData(t, data string) error {
 	switch t {
 	case SRVType:
-		return nil
 	case TXTType:
-		if len(data) < 0 || len(data) > 512 {
+		if value := TXTValue(data); len(value) > 512 {
 			return errors.New("TXT data must be between 0..512 characters in length")
 		}
 	default:
@@ -232,7 +232,7 @@ func ValidateData(t, data string) error {
 			return errors.New("data must be between 0..255 characters in length")
 		}
 	}
-	return nil
+	return validateTypeData(t, data)
 }
 
 // ValidatePriority performs bounds checking on priority element
@@ -267,9 +267,117 @@ func IsDefaultNSRecord(record *DomainRecord) bool {
 	return record.Name == Ptr && record.Type == NSType && record.TTL == DefaultTTL
 }
 
-// IsDisallowed prevents empty NS|SOA record lists from being propagated, which is disallowed
+// Owners identify the godaddy_domain_record attribute that an apex A or NS
+// record is stored in
+const (
+	OwnerAddresses   = "addresses"
+	OwnerNameservers = "nameservers"
+	OwnerRecord      = "record"
+)
+
+// OwnershipKey identifies an apex A or NS record within an ownership map. An
+// empty key is returned for any other record, since they are always stored
+// in the record set.
+func OwnershipKey(record *DomainRecord) string {
+	t := strings.ToUpper(record.Type)
+	if record.Name != Ptr || (t != AType && t != NSType) {
+		return ""
+	}
+	return t + " " + strings.ToLower(strings.TrimSuffix(NormalizeData(t, record.Data), "."))
+}
+
+// RecordOwner returns the attribute that a fetched record belongs to. The
+// ownership map, keyed by OwnershipKey, takes precedence. Apex records that
+// it doesn't cover (e.g. on import) fall back to IsDefaultARecord and
+// IsDefaultNSRecord.
+func RecordOwner(ownership map[string]string, record *DomainRecord) string {
+	key := OwnershipKey(record)
+	if key == "" {
+		return OwnerRecord
+	}
+	if owner, ok := ownership[key]; ok {
+		return owner
+	}
+
+	switch {
+	case IsDefaultNSRecord(record):
+		return OwnerNameservers
+	case IsDefaultARecord(record):
+		return OwnerAddresses
+	}
+	return OwnerRecord
+}
+
+// PartitionRecords buckets fetched records the way godaddy_domain_record
+// stores them: default apex A and NS record data populate the addresses and
+// nameservers attributes and every other record is returned as-is, except for
+// SOA records, which are omitted
+func PartitionRecords(records []*DomainRecord) (addresses, nameservers []string, others []*DomainRecord) {
+	return PartitionOwnedRecords(records, nil)
+}
+
+// PartitionOwnedRecords buckets fetched records like PartitionRecords, but
+// places the apex A and NS records according to their RecordOwner
+func PartitionOwnedRecords(records []*DomainRecord, ownership map[string]string) (addresses, nameservers []string, others []*DomainRecord) {
+	addresses = make([]string, 0)
+	nameservers = make([]string, 0)
+	others = make([]*DomainRecord, 0)
+
+	for _, rec := range records {
+		if rec.Type == SOAType {
+			continue
+		}
+		switch RecordOwner(ownership, rec) {
+		case OwnerNameservers:
+			nameservers = append(nameservers, rec.Data)
+		case OwnerAddresses:
+			addresses = append(addresses, rec.Data)
+		default:
+			others = append(others, rec)
+		}
+	}
+	return addresses, nameservers, others
+}
+
+// IsDisallowed prevents empty NS|CAA record lists and SOA records from being propagated, which is disallowed.
+// The SOA timers are only ever changed with SetDomainRecords.
 func IsDisallowed(t string, records []*DomainRecord) bool {
-	return len(records) == 0 && strings.EqualFold(t, NSType) || strings.EqualFold(t, SOAType) || strings.EqualFold(t, CAAType)
+	return len(records) == 0 && (strings.EqualFold(t, NSType) || strings.EqualFold(t, CAAType)) || strings.EqualFold(t, SOAType)
+}
+
+// IsDeletedWhenEmpty is a predicate for record types that cannot be replaced
+// with an empty list, but are removed by name when no records are desired
+func IsDeletedWhenEmpty(t string) bool {
+	return strings.EqualFold(t, CAAType)
+}
+
+// EquivalentData is a predicate that compares record data semantically,
+// ignoring differences in presentation such as quoting
+func EquivalentData(t, a, b string) bool {
+	return a == b || NormalizeData(t, a) == NormalizeData(t, b)
+}
+
+// NormalizeData returns the canonical form of record data: the unquoted and
+// unchunked value of TXT records, CAA records with a quoted value, and host
+// name targets in their A-label form
+func NormalizeData(t, data string) string {
+	switch {
+	case isTargetType(t):
+		if ascii, err := ToASCII(data); err == nil {
+			return ascii
+		}
+		return data
+	}
+
+	switch t {
+	case TXTType:
+		return TXTValue(data)
+	case CAAType:
+		if caa, err := ParseCAA(data); err == nil {
+			return caa.String()
+		}
+	}
+	return data
 }
 
 // IsSupportedType is a predicate used to filter supported domain types
@@ -27,6 +27,36 @@ func TestNewDomainRecord(t *testing.T) {
 	}
 }
 
+func TestPartitionOwnedRecords(t *testing.T) {
+	records := []*DomainRecord{
+		{Type: AType, Name: Ptr, Data: "10.0.0.1", TTL: DefaultTTL},
+		{Type: AType, Name: Ptr, Data: "10.0.0.2", TTL: DefaultTTL},
+		{Type: AType, Name: Ptr, Data: "10.0.0.3", TTL: 600},
+		{Type: NSType, Name: Ptr, Data: "ns1.domaincontrol.com", TTL: DefaultTTL},
+		{Type: NSType, Name: Ptr, Data: "NS2.domaincontrol.com.", TTL: DefaultTTL},
+		{Type: AType, Name: "www", Data: "10.0.0.4", TTL: DefaultTTL},
+		{Type: SOAType, Name: Ptr, Data: "ns1.domaincontrol.com", TTL: DefaultTTL},
+	}
+	ownership := map[string]string{
+		"A 10.0.0.2":                OwnerRecord,
+		"A 10.0.0.3":                OwnerAddresses,
+		"NS ns2.domaincontrol.com":  OwnerRecord,
+		"A 10.0.0.9":                OwnerAddresses,
+		"NS ns9.domaincontrol.com.": OwnerNameservers,
+	}
+
+	addresses, nameservers, others := PartitionOwnedRecords(records, ownership)
+	if len(addresses) != 2 || addresses[0] != "10.0.0.1" || addresses[1] != "10.0.0.3" {
+		t.Errorf("unexpected addresses: %v", addresses)
+	}
+	if len(nameservers) != 1 || nameservers[0] != "ns1.domaincontrol.com" {
+		t.Errorf("unexpected nameservers: %v", nameservers)
+	}
+	if len(others) != 3 || others[0].Data != "10.0.0.2" || others[1].Data != "NS2.domaincontrol.com." || others[2].Name != "www" {
+		t.Errorf("unexpected records: %v", others)
+	}
+}
+
 func randBinaryString(n int) string {
 	var binRunes = []rune("01")
 	out := make([]rune, n)
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+const (
+	// maxHostnameLen is the maximum length of a host name, excluding the
+	// trailing dot
+	maxHostnameLen = 253
+	maxLabelLen    = 63
+)
+
+// validateTypeData applies the data rules specific to a record type
+func validateTypeData(t, data string) error {
+	switch t {
+	case AType:
+		return ValidateIPv4(data)
+	case AAAAType:
+		return ValidateIPv6(data)
+	case CNameType, MXType, NSType:
+		if err := ValidateTarget(data); err != nil {
+			return fmt.Errorf("%s data: %w", t, err)
+		}
+	case SRVType:
+		return ValidateSRVTarget(data)
+	case CAAType:
+		return ValidateCAA(data)
+	case SOAType:
+		_, err := ParseSOA(data)
+		return err
+	}
+	return nil
+}
+
+// ValidateIPv4 ensures that data is an IPv4 address
+func ValidateIPv4(data string) error {
+	addr, err := netip.ParseAddr(data)
+	if err != nil || !addr.Is4() {
+		return fmt.Errorf("A data must be an IPv4 address: %q", data)
+	}
+	return nil
+}
+
+// ValidateIPv6 ensures that data is an IPv6 address
+func ValidateIPv6(data string) error {
+	addr, err := netip.ParseAddr(data)
+	if err != nil || !addr.Is6() || addr.Zone() != "" {
+		return fmt.Errorf("AAAA data must be an IPv6 address: %q", data)
+	}
+	return nil
+}
+
+// ValidateTarget ensures that data is a host name that a CNAME, MX or NS
+// record may point at: "@" for the apex, or an RFC 1123 host name with an
+// optional trailing dot. Underscores are permitted, since they are common in
+// service labels such as _domainconnect, and internationalized names are
+// validated in their A-label form.
+func ValidateTarget(data string) error {
+	if data == Ptr {
+		return nil
+	}
+	if _, err := netip.ParseAddr(data); err == nil {
+		return fmt.Errorf("invalid host name %q: must not be an IP address", data)
+	}
+
+	ascii, err := ToASCII(data)
+	if err != nil {
+		return err
+	}
+	return ValidateHostname(ascii)
+}
+
+// isTargetType is a predicate for record types whose data is a host name
+func isTargetType(t string) bool {
+	switch strings.ToUpper(t) {
+	case CNameType, MXType, NSType, SRVType:
+		return true
+	}
+	return false
+}
+
+// ValidateHostname performs RFC 1123 syntax checks on a host name
+func ValidateHostname(name string) error {
+	host := strings.TrimSuffix(name, ".")
+	if host == "" {
+		return fmt.Errorf("invalid host name %q: must not be empty", name)
+	}
+	if len(host) > maxHostnameLen {
+		return fmt.Errorf("invalid host name %q: must be at most %d characters", name, maxHostnameLen)
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > maxLabelLen {
+			return fmt.Errorf("invalid host name %q: labels must be between 1..%d characters", name, maxLabelLen)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("invalid host name %q: labels must not start or end with a hyphen", name)
+		}
+		for _, c := range label {
+			if !isHostnameChar(c) {
+				return fmt.Errorf("invalid host name %q: invalid character %q", name, c)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateSRVTarget ensures that an SRV target is a host name, or "." to
+// indicate that the service is decidedly not available (RFC 2782)
+func ValidateSRVTarget(data string) error {
+	if data == "." {
+		return nil
+	}
+	if err := ValidateTarget(data); err != nil {
+		return fmt.Errorf("SRV target: %w", err)
+	}
+	return nil
+}
+
+// ValidateCAA checks CAA data in its presentation format: <flags> <tag>
+// <value> (RFC 8659). The value is checked according to its tag, as
+// described by ParseCAA.
+func ValidateCAA(data string) error {
+	_, err := ParseCAA(data)
+	return err
+}
+
+func isHostnameChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateData(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Type     string
+		Data     string
+		Negative bool
+	}{
+		{"Given an IPv4 address", AType, "127.0.0.1", false},
+		{"Given an A record that is not an IP", AType, "not-an-ip", true},
+		{"Given an A record with an IPv6 address", AType, "::1", true},
+		{"Given an IPv6 address", AAAAType, "2001:db8::1", false},
+		{"Given an AAAA record with an IPv4 address", AAAAType, "127.0.0.1", true},
+		{"Given an AAAA record with a zone", AAAAType, "fe80::1%eth0", true},
+		{"Given a CNAME to the apex", CNameType, Ptr, false},
+		{"Given a CNAME with underscores", CNameType, "_domainconnect.gd.domaincontrol.com", false},
+		{"Given a CNAME with a trailing dot", CNameType, "www.example.com.", false},
+		{"Given a CNAME to an IP address", CNameType, "10.0.0.1", true},
+		{"Given an MX pointing at a URL", MXType, "https://mail.example.com", true},
+		{"Given an MX with an empty label", MXType, "mail..example.com", true},
+		{"Given an NS with a leading hyphen", NSType, "-ns1.example.com", true},
+		{"Given an NS with a long label", NSType, strings.Repeat("a", 64) + ".com", true},
+		{"Given an SRV target", SRVType, "ldap.example.com", false},
+		{"Given an unavailable SRV service", SRVType, ".", false},
+		{"Given an SRV target that is an IP", SRVType, "10.0.0.1", true},
+		{"Given a CAA record", CAAType, `0 issue "letsencrypt.org"`, false},
+		{"Given a CAA record without a value", CAAType, "0 issue", true},
+		{"Given a CAA record with invalid flags", CAAType, `256 issue "letsencrypt.org"`, true},
+		{"Given a CAA record with an invalid tag", CAAType, `0 is-sue "letsencrypt.org"`, true},
+		{"Given a CAA record with an unterminated value", CAAType, `0 issue "letsencrypt.org`, true},
+		{"Given a TXT record", TXTType, "v=spf1 -all", false},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			err := ValidateData(test.Type, test.Data)
+			if err != nil && !test.Negative {
+				t.Errorf("expected %s data %q to be valid: %s", test.Type, test.Data, err)
+			}
+			if err == nil && test.Negative {
+				t.Errorf("expected %s data %q to be invalid", test.Type, test.Data)
+			}
+		})
+	}
+}
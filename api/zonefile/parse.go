@@ -0,0 +1,351 @@
+package zonefile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+// ErrUnsupportedType is returned for record types that GoDaddy does not manage
+var ErrUnsupportedType = errors.New("unsupported record type")
+
+// LineError describes a failure to parse a single master file entry
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Errors collects the failures of every invalid entry in a master file
+type Errors []*LineError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Zone is the result of parsing a master file
+type Zone struct {
+	// Origin is the zone apex without a trailing dot
+	Origin  string
+	Records []*api.DomainRecord
+}
+
+// parser tracks the directives in effect while parsing entries
+type parser struct {
+	zone   string
+	origin string
+	ttl    int
+	owner  string
+}
+
+// Parse reads a master file into domain records. Names are made relative to
+// origin, which may be empty if the file declares $ORIGIN. Every record is
+// validated by api.NewDomainRecord; all invalid entries are reported.
+func Parse(r io.Reader, origin string) ([]*api.DomainRecord, error) {
+	zone, err := ParseZone(r, origin)
+	if err != nil {
+		return nil, err
+	}
+	return zone.Records, nil
+}
+
+// ParseZone reads a master file, returning its origin and records
+func ParseZone(r io.Reader, origin string) (*Zone, error) {
+	entries, err := scan(r)
+	if err != nil {
+		return nil, err
+	}
+
+	origin = canonical(origin)
+	p := &parser{zone: origin, origin: origin, ttl: api.DefaultTTL}
+	records := make([]*api.DomainRecord, 0, len(entries))
+
+	var errs Errors
+	for _, e := range entries {
+		rec, err := p.parseEntry(e)
+		if err != nil {
+			errs = append(errs, &LineError{Line: e.line, Err: err})
+			continue
+		}
+		if rec != nil {
+			records = append(records, rec)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if p.zone == "" {
+		return nil, errors.New("origin is required")
+	}
+	return &Zone{Origin: p.zone, Records: records}, nil
+}
+
+func (p *parser) parseEntry(e entry) (*api.DomainRecord, error) {
+	tokens := e.tokens
+	if !e.indented && !tokens[0].quoted && strings.HasPrefix(tokens[0].value, "$") {
+		return nil, p.parseDirective(tokens)
+	}
+
+	if !e.indented {
+		p.owner = tokens[0].value
+		tokens = tokens[1:]
+	} else if p.owner == "" {
+		return nil, errors.New("missing owner name")
+	}
+	if p.origin == "" {
+		return nil, errors.New("$ORIGIN must precede the first record")
+	}
+
+	ttl := p.ttl
+	for i := 0; i < 2 && len(tokens) > 0; i++ {
+		if v, ok := parseTTL(tokens[0].value); ok {
+			ttl = v
+			tokens = tokens[1:]
+		} else if strings.EqualFold(tokens[0].value, class) {
+			tokens = tokens[1:]
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("missing record type")
+	}
+
+	t := strings.ToUpper(tokens[0].value)
+	rdata := tokens[1:]
+	if !api.IsSupportedType(t) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+
+	name, err := p.relative(p.owner)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseRecord(name, t, ttl, rdata)
+}
+
+func (p *parser) parseDirective(tokens []token) error {
+	directive := strings.ToUpper(tokens[0].value)
+	switch directive {
+	case "$ORIGIN":
+		if len(tokens) != 2 || !strings.HasSuffix(tokens[1].value, ".") {
+			return errors.New("$ORIGIN requires an absolute domain name")
+		}
+		p.origin = canonical(tokens[1].value)
+		if p.zone == "" {
+			p.zone = p.origin
+		}
+		if p.origin != p.zone && !strings.HasSuffix(p.origin, "."+p.zone) {
+			return fmt.Errorf("$ORIGIN %s is outside of zone %s", p.origin, p.zone)
+		}
+	case "$TTL":
+		if len(tokens) != 2 {
+			return errors.New("$TTL requires a single value")
+		}
+		ttl, ok := parseTTL(tokens[1].value)
+		if !ok {
+			return fmt.Errorf("invalid $TTL: %s", tokens[1].value)
+		}
+		p.ttl = ttl
+	default:
+		return fmt.Errorf("unsupported directive: %s", directive)
+	}
+	return nil
+}
+
+func (p *parser) parseRecord(name, t string, ttl int, rdata []token) (*api.DomainRecord, error) {
+	var opts []api.DomainRecordOpt
+	var data string
+
+	switch t {
+	case api.AType, api.AAAAType:
+		if err := expectFields(t, rdata, 1); err != nil {
+			return nil, err
+		}
+		data = rdata[0].value
+	case api.CNameType, api.NSType:
+		if err := expectFields(t, rdata, 1); err != nil {
+			return nil, err
+		}
+		data = p.target(rdata[0].value)
+	case api.MXType:
+		if err := expectFields(t, rdata, 2); err != nil {
+			return nil, err
+		}
+		priority, err := strconv.Atoi(rdata[0].value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MX preference: %s", rdata[0].value)
+		}
+		data = p.target(rdata[1].value)
+		opts = append(opts, api.Priority(priority))
+	case api.SRVType:
+		return p.parseSRV(name, ttl, rdata)
+	case api.TXTType:
+		if len(rdata) == 0 {
+			return nil, errors.New("TXT record requires at least one string")
+		}
+		var b strings.Builder
+		for _, tok := range rdata {
+			b.WriteString(tok.value)
+		}
+		data = b.String()
+	case api.CAAType:
+		if err := expectFields(t, rdata, 3); err != nil {
+			return nil, err
+		}
+		data = fmt.Sprintf("%s %s %s", rdata[0].value, rdata[1].value, strconv.Quote(rdata[2].value))
+	case api.SOAType:
+		if err := expectFields(t, rdata, 7); err != nil {
+			return nil, err
+		}
+		fields := make([]string, len(rdata))
+		for i, tok := range rdata {
+			fields[i] = tok.value
+		}
+		fields[0] = p.absolute(fields[0])
+		fields[1] = p.absolute(fields[1])
+		data = strings.Join(fields, " ")
+	}
+
+	return api.NewDomainRecord(name, t, data, ttl, opts...)
+}
+
+// parseSRV splits an owner of the form _service._proto[.name]
+func (p *parser) parseSRV(name string, ttl int, rdata []token) (*api.DomainRecord, error) {
+	if err := expectFields(api.SRVType, rdata, 4); err != nil {
+		return nil, err
+	}
+
+	labels := strings.SplitN(name, ".", 3)
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return nil, fmt.Errorf("SRV owner must be of the form _service._proto.name: %s", name)
+	}
+	host := api.Ptr
+	if len(labels) == 3 {
+		host = labels[2]
+	}
+
+	values := make([]int, 3)
+	for i, field := range []string{"priority", "weight", "port"} {
+		v, err := strconv.Atoi(rdata[i].value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SRV %s: %s", field, rdata[i].value)
+		}
+		values[i] = v
+	}
+
+	return api.NewDomainRecord(host, api.SRVType, p.target(rdata[3].value), ttl,
+		api.Service(labels[0]),
+		api.Protocol(labels[1]),
+		api.Priority(values[0]),
+		api.Weight(values[1]),
+		api.Port(values[2]))
+}
+
+// relative converts an owner name into a name relative to the zone apex
+func (p *parser) relative(name string) (string, error) {
+	fqdn := p.qualify(name)
+	switch {
+	case fqdn == p.zone:
+		return api.Ptr, nil
+	case strings.HasSuffix(fqdn, "."+p.zone):
+		return strings.TrimSuffix(fqdn, "."+p.zone), nil
+	}
+	return "", fmt.Errorf("name %s is outside of zone %s", name, p.zone)
+}
+
+// target converts a host name into the form stored by GoDaddy: "@" for the
+// apex and fully qualified names without a trailing dot otherwise
+func (p *parser) target(name string) string {
+	if name == api.Ptr && p.origin == p.zone {
+		return api.Ptr
+	}
+	if name == "." {
+		return name
+	}
+	return p.qualify(name)
+}
+
+// absolute qualifies a name and appends the trailing dot
+func (p *parser) absolute(name string) string {
+	return p.qualify(name) + "."
+}
+
+// qualify resolves a name against the current origin, without a trailing dot
+func (p *parser) qualify(name string) string {
+	switch {
+	case name == api.Ptr:
+		return p.origin
+	case strings.HasSuffix(name, "."):
+		return canonical(name)
+	}
+	return canonical(name) + "." + p.origin
+}
+
+func expectFields(t string, rdata []token, n int) error {
+	if len(rdata) != n {
+		return fmt.Errorf("%s record requires %d data fields, found %d", t, n, len(rdata))
+	}
+	return nil
+}
+
+// parseTTL parses a TTL in seconds or using BIND units (e.g. 1h30m)
+func parseTTL(s string) (int, bool) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, false
+	}
+	if v, err := strconv.Atoi(s); err == nil {
+		return v, true
+	}
+
+	total, current := 0, 0
+	for _, c := range strings.ToLower(s) {
+		if c >= '0' && c <= '9' {
+			current = current*10 + int(c-'0')
+			continue
+		}
+
+		var unit int
+		switch c {
+		case 's':
+			unit = 1
+		case 'm':
+			unit = 60
+		case 'h':
+			unit = 3600
+		case 'd':
+			unit = 86400
+		case 'w':
+			unit = 604800
+		default:
+			return 0, false
+		}
+		total += current * unit
+		current = 0
+	}
+	return total + current, true
+}
+
+// canonical lower cases a name and converts it into its A-label form. Names
+// that are not valid IDNs are left for api.NewDomainRecord to reject.
+func canonical(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	if ascii, err := api.ToASCII(name); err == nil {
+		return ascii
+	}
+	return name
+}
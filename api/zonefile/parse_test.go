@@ -0,0 +1,157 @@
+package zonefile
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const testZone = `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.domaincontrol.com. dns.jomax.net. (
+		2023010101 ; serial
+		28800      ; refresh
+		7200       ; retry
+		604800     ; expire
+		600 )      ; minimum
+	IN	NS	ns1.domaincontrol.com.
+	IN	NS	ns2.domaincontrol.com.
+@	600	IN	A	192.168.1.2
+www.example.com.	CNAME	@
+mail		MX	10 mail.example.com.
+@		MX	20 backup
+@		TXT	"v=spf1 include:\"_spf\".example.com" " ~all"
+note	TXT	( "multi"
+		  "line" )
+_ldap._tcp	SRV	1 5 389 ldap
+@	CAA	0 issue "letsencrypt.org"
+$ORIGIN sub.example.com.
+api	AAAA	2001:db8::1
+`
+
+func TestParse(t *testing.T) {
+	records, err := Parse(strings.NewReader(testZone), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := 389
+	expected := []*api.DomainRecord{
+		{Type: api.SOAType, Name: api.Ptr, Data: "ns1.domaincontrol.com. dns.jomax.net. 2023010101 28800 7200 604800 600", TTL: 3600},
+		{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: 3600},
+		{Type: api.NSType, Name: api.Ptr, Data: "ns2.domaincontrol.com", TTL: 3600},
+		{Type: api.AType, Name: api.Ptr, Data: "192.168.1.2", TTL: 600},
+		{Type: api.CNameType, Name: "www", Data: api.Ptr, TTL: 3600},
+		{Type: api.MXType, Name: "mail", Data: "mail.example.com", TTL: 3600, Priority: 10},
+		{Type: api.MXType, Name: api.Ptr, Data: "backup.example.com", TTL: 3600, Priority: 20},
+		{Type: api.TXTType, Name: api.Ptr, Data: `v=spf1 include:"_spf".example.com ~all`, TTL: 3600},
+		{Type: api.TXTType, Name: "note", Data: "multiline", TTL: 3600},
+		{Type: api.SRVType, Name: api.Ptr, Data: "ldap.example.com", TTL: 3600, Service: "_ldap", Protocol: "_tcp", Priority: 1, Weight: 5, Port: &port},
+		{Type: api.CAAType, Name: api.Ptr, Data: `0 issue "letsencrypt.org"`, TTL: 3600},
+		{Type: api.AAAAType, Name: "api.sub", Data: "2001:db8::1", TTL: 3600},
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %d", len(expected), len(records))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(expected[i], records[i]) {
+			t.Errorf("record %d: expected %+v, got %+v", i, expected[i], records[i])
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	records, err := Parse(strings.NewReader(testZone), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Marshal("example.com", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(strings.NewReader(string(b)), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(records) {
+		t.Fatalf("expected %d records, got %d:\n%s", len(records), len(parsed), b)
+	}
+}
+
+func TestParseRoundTripEscapes(t *testing.T) {
+	// \DDD escapes decode to raw bytes that need not be valid UTF-8
+	zone := "@\tTXT\t\"" + strings.Repeat(`\150`, 300) + "\"\n"
+	records, err := Parse(strings.NewReader(zone), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := strings.Repeat("\x96", 300); len(records) != 1 || records[0].Data != expected {
+		t.Fatalf("expected a single TXT record of 300 raw bytes, got %+v", records)
+	}
+
+	line, err := FormatRecord(records[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(strings.NewReader(line+"\n"), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 1 || parsed[0].Data != records[0].Data {
+		t.Errorf("expected the escaped bytes to round trip, got %q", line)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	zone := `$ORIGIN example.com.
+@	PTR	host.example.com.
+www	A	not-an-ip-but-way-too-long-` + strings.Repeat("x", 255) + `
+other.example.org.	A	192.168.1.2
+@	MX	mail.example.com.
+`
+	_, err := Parse(strings.NewReader(zone), "")
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected line errors, got %v", err)
+	}
+
+	lines := make([]int, len(errs))
+	for i, e := range errs {
+		lines[i] = e.Line
+	}
+	if !reflect.DeepEqual([]int{2, 3, 4, 5}, lines) {
+		t.Errorf("unexpected error lines %v: %s", lines, err)
+	}
+	if !errors.Is(errs[0], ErrUnsupportedType) {
+		t.Errorf("expected an unsupported type error, got %s", errs[0])
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	var criteria = []struct {
+		Value    string
+		Expected int
+		Valid    bool
+	}{
+		{"3600", 3600, true},
+		{"1h30m", 5400, true},
+		{"1w", 604800, true},
+		{"IN", 0, false},
+		{"1x", 0, false},
+	}
+	for _, test := range criteria {
+		t.Run(test.Value, func(t *testing.T) {
+			ttl, ok := parseTTL(test.Value)
+			if ok != test.Valid || ttl != test.Expected {
+				t.Errorf("expected (%d, %t), got (%d, %t)", test.Expected, test.Valid, ttl, ok)
+			}
+		})
+	}
+}
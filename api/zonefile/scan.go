@@ -0,0 +1,175 @@
+package zonefile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// token is a single field within a master file entry
+type token struct {
+	value  string
+	quoted bool
+}
+
+// entry is a logical master file line, which may span several physical
+// lines when parentheses are used
+type entry struct {
+	line     int
+	indented bool
+	tokens   []token
+}
+
+// scanner splits master file content into entries, handling comments,
+// parentheses, quoted strings and escape sequences
+type scanner struct {
+	src     []byte
+	pos     int
+	line    int
+	depth   int
+	entries []entry
+	current *entry
+	buf     bytes.Buffer
+	inToken bool
+	quoted  bool
+}
+
+func scan(r io.Reader) ([]entry, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &scanner{src: src, line: 1}
+	if err := s.run(); err != nil {
+		return nil, &LineError{Line: s.line, Err: err}
+	}
+	return s.entries, nil
+}
+
+func (s *scanner) run() error {
+	atLineStart := true
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+
+		if atLineStart && s.depth == 0 {
+			s.current = &entry{line: s.line, indented: c == ' ' || c == '\t'}
+		}
+		atLineStart = false
+
+		switch {
+		case s.quoted:
+			if err := s.scanQuoted(c); err != nil {
+				return err
+			}
+			continue
+		case c == '\n':
+			s.endToken()
+			s.line++
+			if s.depth == 0 {
+				s.endEntry()
+			}
+			atLineStart = true
+		case c == ' ' || c == '\t' || c == '\r':
+			s.endToken()
+		case c == ';':
+			s.endToken()
+			for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+				s.pos++
+			}
+			continue
+		case c == '(':
+			s.endToken()
+			s.depth++
+		case c == ')':
+			s.endToken()
+			if s.depth == 0 {
+				return errors.New("unbalanced parentheses")
+			}
+			s.depth--
+		case c == '"':
+			s.endToken()
+			s.quoted = true
+			s.inToken = true
+		case c == '\\':
+			if err := s.scanEscape(); err != nil {
+				return err
+			}
+			s.inToken = true
+			continue
+		default:
+			s.buf.WriteByte(c)
+			s.inToken = true
+		}
+		s.pos++
+	}
+
+	if s.quoted {
+		return errors.New("unterminated quoted string")
+	}
+	if s.depth > 0 {
+		return errors.New("unbalanced parentheses")
+	}
+	s.endToken()
+	s.endEntry()
+	return nil
+}
+
+func (s *scanner) scanQuoted(c byte) error {
+	switch c {
+	case '"':
+		s.endToken()
+		s.pos++
+	case '\\':
+		return s.scanEscape()
+	case '\n':
+		return errors.New("unterminated quoted string")
+	default:
+		s.buf.WriteByte(c)
+		s.pos++
+	}
+	return nil
+}
+
+// scanEscape decodes \X and \DDD escape sequences
+func (s *scanner) scanEscape() error {
+	s.pos++
+	if s.pos >= len(s.src) {
+		return errors.New("incomplete escape sequence")
+	}
+
+	c := s.src[s.pos]
+	if c < '0' || c > '9' {
+		s.buf.WriteByte(c)
+		s.pos++
+		return nil
+	}
+
+	if s.pos+3 > len(s.src) {
+		return errors.New("incomplete escape sequence")
+	}
+	n, err := strconv.Atoi(string(s.src[s.pos : s.pos+3]))
+	if err != nil || n > 255 {
+		return errors.New("invalid escape sequence")
+	}
+	s.buf.WriteByte(byte(n))
+	s.pos += 3
+	return nil
+}
+
+func (s *scanner) endToken() {
+	if s.inToken && s.current != nil {
+		s.current.tokens = append(s.current.tokens, token{value: s.buf.String(), quoted: s.quoted})
+	}
+	s.buf.Reset()
+	s.inToken = false
+	s.quoted = false
+}
+
+func (s *scanner) endEntry() {
+	if s.current != nil && len(s.current.tokens) > 0 {
+		s.entries = append(s.entries, *s.current)
+	}
+	s.current = nil
+}
@@ -0,0 +1,19 @@
+package zonefile
+
+import (
+	"bytes"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+// init registers the zone file snapshot format, which cannot be declared by
+// the api package without an import cycle
+func init() {
+	api.RegisterSnapshotFormat(api.SnapshotZone, api.SnapshotFormat{
+		Extension: "zone",
+		Marshal:   Marshal,
+		Unmarshal: func(domain string, data []byte) ([]*api.DomainRecord, error) {
+			return Parse(bytes.NewReader(data), domain)
+		},
+	})
+}
@@ -0,0 +1,176 @@
+// Package zonefile converts GoDaddy domain records to and from RFC 1035
+// master (BIND zone) files.
+package zonefile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const class = "IN"
+
+// typeOrder determines the order of record types in rendered zone files
+var typeOrder = map[string]int{
+	api.SOAType:   0,
+	api.NSType:    1,
+	api.AType:     2,
+	api.AAAAType:  3,
+	api.CNameType: 4,
+	api.MXType:    5,
+	api.TXTType:   6,
+	api.SRVType:   7,
+	api.CAAType:   8,
+}
+
+// Marshal renders records as a master file for the origin
+func Marshal(origin string, records []*api.DomainRecord) ([]byte, error) {
+	var b bytes.Buffer
+	if err := Write(&b, origin, records); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Write renders records as a master file for the origin. The $TTL directive
+// uses the most common record TTL, and records are grouped by type.
+func Write(w io.Writer, origin string, records []*api.DomainRecord) error {
+	origin = strings.TrimSuffix(strings.TrimSpace(origin), ".")
+	if origin == "" {
+		return fmt.Errorf("origin is required")
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "$ORIGIN %s.\n", origin)
+	fmt.Fprintf(bw, "$TTL %d\n", defaultTTL(records))
+
+	sorted := make([]*api.DomainRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if typeOrder[a.Type] != typeOrder[b.Type] {
+			return typeOrder[a.Type] < typeOrder[b.Type]
+		}
+		return ownerName(a) < ownerName(b)
+	})
+
+	for _, rec := range sorted {
+		line, err := FormatRecord(rec)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(bw, line)
+	}
+	return bw.Flush()
+}
+
+// FormatRecord renders a single record as a master file entry relative to
+// the zone origin
+func FormatRecord(rec *api.DomainRecord) (string, error) {
+	rdata, err := formatData(rec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s\t%d\t%s\t%s\t%s", ownerName(rec), rec.TTL, class, rec.Type, rdata), nil
+}
+
+// ownerName returns the owner of a record. SRV records are rendered in
+// _service._proto.name form.
+func ownerName(rec *api.DomainRecord) string {
+	name := rec.Name
+	if name == "" {
+		name = api.Ptr
+	}
+	if rec.Type != api.SRVType || rec.Service == "" || rec.Protocol == "" {
+		return name
+	}
+
+	owner := rec.Service + "." + rec.Protocol
+	if name != api.Ptr {
+		owner += "." + name
+	}
+	return owner
+}
+
+func formatData(rec *api.DomainRecord) (string, error) {
+	switch rec.Type {
+	case api.AType, api.AAAAType, api.SOAType:
+		return rec.Data, nil
+	case api.CAAType:
+		if caa, err := api.ParseCAA(rec.Data); err == nil {
+			return caa.String(), nil
+		}
+		return rec.Data, nil
+	case api.CNameType, api.NSType:
+		return absolute(rec.Data), nil
+	case api.MXType:
+		return fmt.Sprintf("%d %s", rec.Priority, absolute(rec.Data)), nil
+	case api.SRVType:
+		port := 0
+		if rec.Port != nil {
+			port = *rec.Port
+		}
+		return fmt.Sprintf("%d %d %d %s", rec.Priority, rec.Weight, port, absolute(rec.Data)), nil
+	case api.TXTType:
+		return QuoteTXT(api.TXTValue(rec.Data)), nil
+	}
+	return "", fmt.Errorf("unsupported record type: %s", rec.Type)
+}
+
+// absolute qualifies a target host name. GoDaddy stores targets as fully
+// qualified names without a trailing dot, or "@" for the apex.
+func absolute(target string) string {
+	if target == api.Ptr || strings.HasSuffix(target, ".") {
+		return target
+	}
+	return target + "."
+}
+
+// QuoteTXT renders TXT data as one or more quoted <character-string>s of at
+// most 255 bytes each
+func QuoteTXT(data string) string {
+	chunks := api.ChunkTXT(data)
+	for i, chunk := range chunks {
+		chunks[i] = quote(chunk)
+	}
+	return strings.Join(chunks, " ")
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c > '~':
+			b.WriteString(fmt.Sprintf("\\%03d", c))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func defaultTTL(records []*api.DomainRecord) int {
+	counts := make(map[int]int)
+	for _, rec := range records {
+		counts[rec.TTL]++
+	}
+
+	ttl, max := api.DefaultTTL, 0
+	for t, n := range counts {
+		if n > max || (n == max && t < ttl) {
+			ttl, max = t, n
+		}
+	}
+	return ttl
+}
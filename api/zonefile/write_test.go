@@ -0,0 +1,94 @@
+package zonefile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+func TestMarshal(t *testing.T) {
+	port := 389
+	records := []*api.DomainRecord{
+		{Type: api.TXTType, Name: api.Ptr, Data: `v=spf1 include:"_spf".example.com ~all`, TTL: 3600},
+		{Type: api.MXType, Name: api.Ptr, Data: "mail.example.com", TTL: 600, Priority: 10},
+		{Type: api.SRVType, Name: api.Ptr, Data: "ldap.example.com", TTL: 3600, Service: "_ldap", Protocol: "_tcp", Priority: 1, Weight: 5, Port: &port},
+		{Type: api.CNameType, Name: "www", Data: "@", TTL: 3600},
+		{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: 3600},
+		{Type: api.AType, Name: api.Ptr, Data: "192.168.1.2", TTL: 3600},
+		{Type: api.CAAType, Name: api.Ptr, Data: `0 issue "letsencrypt.org"`, TTL: 3600},
+	}
+
+	b, err := Marshal("example.com.", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"$ORIGIN example.com.",
+		"$TTL 3600",
+		"@\t3600\tIN\tNS\tns1.domaincontrol.com.",
+		"@\t3600\tIN\tA\t192.168.1.2",
+		"www\t3600\tIN\tCNAME\t@",
+		"@\t600\tIN\tMX\t10 mail.example.com.",
+		"@\t3600\tIN\tTXT\t\"v=spf1 include:\\\"_spf\\\".example.com ~all\"",
+		"_ldap._tcp\t3600\tIN\tSRV\t1 5 389 ldap.example.com.",
+		"@\t3600\tIN\tCAA\t0 issue \"letsencrypt.org\"",
+		"",
+	}, "\n")
+	if string(b) != expected {
+		t.Errorf("unexpected zone file:\n%s\nexpected:\n%s", b, expected)
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Data     string
+		Expected string
+	}{
+		{"Given an empty string", "", `""`},
+		{"Given control characters", "a\tb", `"a\009b"`},
+		{"Given a long string", strings.Repeat("a", 300), `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := QuoteTXT(test.Data); got != test.Expected {
+				t.Errorf("expected %s, got %s", test.Expected, got)
+			}
+		})
+	}
+}
+
+func TestOwnerName(t *testing.T) {
+	rec := &api.DomainRecord{Type: api.SRVType, Name: "dc", Service: "_ldap", Protocol: "_tcp"}
+	if got := ownerName(rec); got != "_ldap._tcp.dc" {
+		t.Errorf("unexpected owner: %s", got)
+	}
+}
+
+func TestZoneSnapshot(t *testing.T) {
+	records := []*api.DomainRecord{
+		{Type: api.AType, Name: "www", Data: "10.0.0.1", TTL: 3600},
+		{Type: api.TXTType, Name: api.Ptr, Data: "v=spf1 -all", TTL: 3600},
+	}
+
+	path, err := api.WriteSnapshot(t.TempDir(), api.SnapshotZone, "example.com", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, ".zone") {
+		t.Errorf("expected a zone file, got %s", path)
+	}
+
+	restored, err := api.ReadSnapshot(path, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 2 {
+		t.Fatalf("expected the records to round trip, got %v", restored)
+	}
+	if _, err := api.ReadSnapshot(path, "example.net"); err == nil {
+		t.Error("expected a snapshot of another domain to be rejected")
+	}
+}
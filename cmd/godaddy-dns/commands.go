@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/n3integration/terraform-provider-godaddy/api/hclgen"
+	"github.com/n3integration/terraform-provider-godaddy/api/zonefile"
+)
+
+func runDomains(e *env, args []string) error {
+	flags := newFlagSet("domains", e)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	domains, err := e.client.GetDomains(e.customer)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDOMAIN\tSTATUS")
+	for _, d := range domains {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", d.ID, d.Name, d.Status)
+	}
+	return w.Flush()
+}
+
+func runRecords(e *env, args []string) error {
+	flags := newFlagSet("records", e)
+	output := flags.String("o", "table", "output format: table, json or zone")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	domain := flags.Arg(0)
+	records, err := e.client.GetDomainRecords(e.customer, domain)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "table":
+		return writeTable(e, records)
+	case "json":
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "zone":
+		return zonefile.Write(e.stdout, domain, records)
+	}
+	return fmt.Errorf("unsupported output format: %s", *output)
+}
+
+func runSet(e *env, args []string) error {
+	flags := newFlagSet("set", e)
+	ttl := flags.Int("ttl", api.DefaultTTL, "time-to-live in seconds")
+	priority := flags.Int("priority", api.DefaultPriority, "MX or SRV priority")
+	weight := flags.Int("weight", api.DefaultWeight, "SRV weight")
+	port := flags.Int("port", api.DefaultPort, "SRV port")
+	service := flags.String("service", "", "SRV service (e.g. _ldap)")
+	protocol := flags.String("protocol", "", "SRV protocol (e.g. _tcp)")
+	if err := flags.Parse(args); err != nil || flags.NArg() < 4 {
+		flags.Usage()
+		return errUsage
+	}
+
+	domain, t, name := flags.Arg(0), strings.ToUpper(flags.Arg(1)), flags.Arg(2)
+	records := make([]*api.DomainRecord, 0, flags.NArg()-3)
+	for _, data := range flags.Args()[3:] {
+		rec, err := api.NewDomainRecord(name, t, data, *ttl,
+			api.Priority(*priority),
+			api.Weight(*weight),
+			api.Port(*port),
+			api.Service(*service),
+			api.Protocol(*protocol))
+		if err != nil {
+			return err
+		}
+		records = append(records, rec)
+	}
+
+	if err := e.client.SetDomainRecords(e.customer, domain, t, name, records); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "Set %d %s record(s) for %s on %s\n", len(records), t, name, domain)
+	return nil
+}
+
+func runDelete(e *env, args []string) error {
+	flags := newFlagSet("delete", e)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 3 {
+		flags.Usage()
+		return errUsage
+	}
+
+	domain, t, name := flags.Arg(0), strings.ToUpper(flags.Arg(1)), flags.Arg(2)
+	if err := e.client.DeleteDomainRecords(e.customer, domain, t, name); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "Deleted %s records for %s on %s\n", t, name, domain)
+	return nil
+}
+
+func runApply(e *env, args []string) error {
+	flags := newFlagSet("apply", e)
+	yes := flags.Bool("yes", false, "apply without prompting for confirmation")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		flags.Usage()
+		return errUsage
+	}
+
+	domain := flags.Arg(0)
+	f, err := os.Open(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	desired, err := zonefile.Parse(f, domain)
+	if err != nil {
+		return err
+	}
+
+	ok, err := preview(e, domain, desired, "the zone file", "Apply", *yes)
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := e.client.UpdateDomainRecords(e.customer, domain, desired); err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, "Apply complete.")
+	return nil
+}
+
+func runRestore(e *env, args []string) error {
+	flags := newFlagSet("restore", e)
+	yes := flags.Bool("yes", false, "restore without prompting for confirmation")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		flags.Usage()
+		return errUsage
+	}
+
+	domain, path := flags.Arg(0), flags.Arg(1)
+	desired, err := api.ReadSnapshot(path, domain)
+	if err != nil {
+		return err
+	}
+
+	ok, err := preview(e, domain, desired, "the snapshot", "Restore", *yes)
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := api.RestoreSnapshot(e.client, e.customer, domain, path); err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, "Restore complete.")
+	return nil
+}
+
+// preview shows the changes required to converge the domain to the desired
+// records and prompts for confirmation, returning whether to proceed
+func preview(e *env, domain string, desired []*api.DomainRecord, source, action string, yes bool) (bool, error) {
+	current, err := e.client.GetDomainRecords(e.customer, domain)
+	if err != nil {
+		return false, err
+	}
+
+	// records of types that are not replaced, such as SOA, are never written
+	diff := api.DiffRecords(api.ReplacedRecords(current, desired), api.ReplacedRecords(desired, desired))
+	if diff.Empty() {
+		fmt.Fprintf(e.stdout, "No changes. The domain matches %s.\n", source)
+		return false, nil
+	}
+
+	fmt.Fprintf(e.stdout, "%s\n\n%d to add, %d to remove.\n", diff, len(diff.Added), len(diff.Removed))
+	if yes {
+		return true, nil
+	}
+
+	ok, err := confirm(e, action+" these changes?")
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		fmt.Fprintf(e.stdout, "%s cancelled.\n", action)
+	}
+	return ok, nil
+}
+
+func runHCL(e *env, args []string) error {
+	flags := newFlagSet("hcl", e)
+	name := flags.String("name", "", "resource name (defaults to the domain with dots replaced by underscores)")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	domain := flags.Arg(0)
+	records, err := e.client.GetDomainRecords(e.customer, domain)
+	if err != nil {
+		return err
+	}
+
+	return hclgen.Write(e.stdout, hclgen.Config{
+		Domain:   domain,
+		Customer: e.customer,
+		Name:     *name,
+	}, records)
+}
+
+func writeTable(e *env, records []*api.DomainRecord) error {
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tNAME\tDATA\tTTL\tPRIORITY\tWEIGHT\tPORT\tSERVICE\tPROTOCOL")
+	for _, rec := range records {
+		port := ""
+		if rec.Port != nil {
+			port = fmt.Sprint(*rec.Port)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\t%s\n",
+			rec.Type, rec.Name, rec.Data, rec.TTL, rec.Priority, rec.Weight, port, rec.Service, rec.Protocol)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,168 @@
+// Command godaddy-dns manages GoDaddy DNS records from the command line using
+// the same API client, credentials and rate limiting as the Terraform
+// provider.
+//
+// Usage:
+//
+//	godaddy-dns [flags] <command> [arguments]
+//
+// The API key and secret are read from GODADDY_API_KEY and GODADDY_API_SECRET.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const defaultBaseURL = "https://api.godaddy.com"
+
+// command is a godaddy-dns subcommand
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(env *env, args []string) error
+}
+
+// env carries the shared state available to every command
+type env struct {
+	client   api.DNSClient
+	customer string
+	stdin    *bufio.Reader
+	stdout   io.Writer
+}
+
+var errUsage = errors.New("invalid usage")
+
+// newClient constructs the API client with the default rate limiting and
+// retry policy, so that bulk runs survive throttling; tests replace it with
+// an in-memory client
+var newClient = func(baseURL, key, secret string, opts ...api.ClientOpt) (api.DNSClient, error) {
+	opts = append([]api.ClientOpt{api.WithRetryPolicy(api.DefaultRetryPolicy)}, opts...)
+	return api.NewClient(baseURL, key, secret, opts...)
+}
+
+var commands []*command
+
+func init() {
+	// assigned in init since the commands refer back to the table for usage
+	commands = []*command{
+		{"domains", "", "List domains", runDomains},
+		{"records", "[-o table|json|zone] <domain>", "Dump the records of a domain", runRecords},
+		{"set", "[-ttl n] [-priority n] [-weight n] [-port n] [-service s] [-protocol s] <domain> <type> <name> <data>...", "Replace the records of a type and name", runSet},
+		{"delete", "<domain> <type> <name>", "Delete the records of a type and name", runDelete},
+		{"apply", "[-yes] <domain> <zonefile>", "Converge a domain to a zone file after previewing the changes", runApply},
+		{"restore", "[-yes] <domain> <snapshot>", "Roll a domain back to a snapshot after previewing the changes", runRestore},
+		{"hcl", "[-name resource] <domain>", "Generate godaddy_domain_record configuration and an import block", runHCL},
+	}
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("godaddy-dns", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	baseURL := flags.String("baseurl", defaultBaseURL, "GoDaddy API base URL")
+	customer := flags.String("customer", "", "customer ID (required if you are a reseller managing a domain outside your account)")
+	snapshotDir := flags.String("snapshot-dir", "", "directory in which to snapshot a domain's records before they are replaced")
+	snapshotFormat := flags.String("snapshot-format", api.SnapshotJSON, "snapshot format: json or zone")
+	flags.Usage = func() { usage(flags) }
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	cmd := lookup(flags.Arg(0))
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command: %s\n", flags.Arg(0))
+		flags.Usage()
+		return errUsage
+	}
+
+	var opts []api.ClientOpt
+	if *snapshotDir != "" {
+		opts = append(opts, api.WithSnapshots(*snapshotDir, *snapshotFormat))
+	}
+
+	client, err := newClient(*baseURL, os.Getenv("GODADDY_API_KEY"), os.Getenv("GODADDY_API_SECRET"), opts...)
+	if err != nil {
+		return err
+	}
+
+	return cmd.run(&env{
+		client:   client,
+		customer: *customer,
+		stdin:    bufio.NewReader(stdin),
+		stdout:   stdout,
+	}, flags.Args()[1:])
+}
+
+func lookup(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func usage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintln(out, "Usage: godaddy-dns [flags] <command> [arguments]")
+	fmt.Fprintln(out, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd := lookup(name)
+		fmt.Fprintf(out, "  %-8s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(out, "           godaddy-dns %s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(out, "\nFlags:")
+	flags.PrintDefaults()
+	fmt.Fprintln(out, "\nEnvironment:\n  GODADDY_API_KEY, GODADDY_API_SECRET")
+}
+
+// newFlagSet constructs the flag set for a subcommand
+func newFlagSet(cmd string, e *env) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	flags.SetOutput(e.stdout)
+	flags.Usage = func() {
+		c := lookup(cmd)
+		fmt.Fprintf(flags.Output(), "Usage: godaddy-dns %s %s\n", c.name, c.usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// confirm prompts for a yes/no answer, defaulting to no
+func confirm(e *env, prompt string) (bool, error) {
+	fmt.Fprintf(e.stdout, "%s [y/N]: ", prompt)
+	answer, err := e.stdin.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const testDomain = "example.com"
+
+func newTestClient(t *testing.T) *api.MemoryClient {
+	client := api.NewMemoryClient()
+	client.AddDomain("", api.Domain{ID: 42, Name: testDomain, Status: "ACTIVE"},
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.AType, Name: "www", Data: "10.0.0.1", TTL: api.DefaultTTL},
+	)
+
+	orig := newClient
+	newClient = func(_, _, _ string, _ ...api.ClientOpt) (api.DNSClient, error) { return client, nil }
+	t.Cleanup(func() { newClient = orig })
+	return client
+}
+
+func execute(t *testing.T, stdin string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String() + stderr.String(), err
+}
+
+func TestDomains(t *testing.T) {
+	newTestClient(t)
+
+	out, err := execute(t, "", "domains")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "42") || !strings.Contains(out, testDomain) {
+		t.Errorf("expected the domain to be listed:\n%s", out)
+	}
+}
+
+func TestRecordsOutputFormats(t *testing.T) {
+	newTestClient(t)
+
+	for format, want := range map[string]string{
+		"table": "10.0.0.1",
+		"json":  `"data": "10.0.0.1"`,
+		"zone":  "www\t3600\tIN\tA\t10.0.0.1",
+	} {
+		out, err := execute(t, "", "records", "-o", format, testDomain)
+		if err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+		if !strings.Contains(out, want) {
+			t.Errorf("%s: expected %q in:\n%s", format, want, out)
+		}
+	}
+
+	if _, err := execute(t, "", "records", "-o", "yaml", testDomain); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestSetAndDelete(t *testing.T) {
+	client := newTestClient(t)
+
+	if _, err := execute(t, "", "set", "-ttl", "3600", testDomain, "a", "www", "10.0.0.2", "10.0.0.3"); err != nil {
+		t.Fatal(err)
+	}
+	records, _ := client.GetDomainRecords("", testDomain)
+	if got := countRecords(records, api.AType, "www"); got != 2 {
+		t.Fatalf("expected 2 A records for www, got %d", got)
+	}
+
+	if _, err := execute(t, "", "delete", testDomain, "A", "www"); err != nil {
+		t.Fatal(err)
+	}
+	records, _ = client.GetDomainRecords("", testDomain)
+	if got := countRecords(records, api.AType, "www"); got != 0 {
+		t.Fatalf("expected the A records for www to be deleted, got %d", got)
+	}
+
+	if _, err := execute(t, "", "set", testDomain, "A", "www"); err == nil {
+		t.Error("expected a usage error without data")
+	}
+}
+
+func TestApply(t *testing.T) {
+	client := newTestClient(t)
+
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	zone := "$TTL 600\n@ IN A 10.0.0.9\nmail IN CNAME @\n"
+	if err := os.WriteFile(path, []byte(zone), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := execute(t, "n\n", "apply", testDomain, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"- A www 10.0.0.1", "+ A @ 10.0.0.9", "cancelled"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	records, _ := client.GetDomainRecords("", testDomain)
+	if got := countRecords(records, api.AType, "www"); got != 1 {
+		t.Fatal("expected a declined apply to leave the domain unchanged")
+	}
+
+	if _, err := execute(t, "", "apply", "-yes", testDomain, path); err != nil {
+		t.Fatal(err)
+	}
+	records, _ = client.GetDomainRecords("", testDomain)
+	if len(records) != 3 || countRecords(records, api.NSType, api.Ptr) != 1 {
+		t.Fatalf("expected the NS record to be preserved alongside the zone file records: %v", records)
+	}
+
+	out, err = execute(t, "", "apply", testDomain, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "No changes") {
+		t.Errorf("expected no changes on a second apply:\n%s", out)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	client := newTestClient(t)
+
+	records, _ := client.GetDomainRecords("", testDomain)
+	path, err := api.WriteSnapshot(t.TempDir(), api.SnapshotJSON, testDomain, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := api.NewDomainRecord("api", api.AType, "10.0.0.2", api.DefaultTTL)
+	if err := client.UpdateDomainRecords("", testDomain, []*api.DomainRecord{a}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := execute(t, "y\n", "restore", testDomain, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"- A api 10.0.0.2", "+ A www 10.0.0.1", "Restore complete"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	records, _ = client.GetDomainRecords("", testDomain)
+	if countRecords(records, api.AType, "www") != 1 || countRecords(records, api.AType, "api") != 0 {
+		t.Fatalf("expected the snapshot to be restored: %v", records)
+	}
+
+	if _, err := execute(t, "", "restore", "example.org", path); err == nil {
+		t.Error("expected a snapshot of another domain to be rejected")
+	}
+}
+
+func TestHCL(t *testing.T) {
+	newTestClient(t)
+
+	out, err := execute(t, "", "hcl", "-name", "example", testDomain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"to = godaddy_domain_record.example", `nameservers = ["ns1.domaincontrol.com"]`, `data = "10.0.0.1"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	newTestClient(t)
+
+	if _, err := execute(t, "", "bogus"); err != errUsage {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
+
+func countRecords(records []*api.DomainRecord, t, name string) int {
+	n := 0
+	for _, rec := range records {
+		if rec.Type == t && rec.Name == name {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,97 @@
+package godaddy
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const (
+	attrRemoteHash = "remote_hash"
+	attrForce      = "force"
+)
+
+func remoteHashSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "A digest of the remote records when they were last read, used to detect changes made outside of Terraform before an update.",
+	}
+}
+
+func forceSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Overwrite the records even if they were changed outside of Terraform since they were last read.",
+	}
+}
+
+// checkRemoteUnchanged guards against overwriting changes made outside of
+// Terraform between plan and apply. The current records are compared with
+// the remote_hash captured by the last read, and any differences from the
+// previous records are reported unless force is set.
+func checkRemoteUnchanged(d *schema.ResourceData, domain string, previous, current []*api.DomainRecord) error {
+	if d.Get(attrForce).(bool) {
+		return nil
+	}
+
+	expected, _ := d.GetChange(attrRemoteHash)
+	if expected.(string) == "" || api.HashRecords(domain, current) == expected.(string) {
+		return nil
+	}
+
+	diff := api.DiffRecords(api.CanonicalRecords(domain, previous), api.CanonicalRecords(domain, current))
+	return fmt.Errorf("the records of %s were changed outside of Terraform since they were last read:\n\n%s\n\n"+
+		"Run terraform plan to review the changes, or set %s = true to overwrite them", domain, diff, attrForce)
+}
+
+// trackedRecords filters the remote records that are stored in the state of
+// a godaddy_domain_record. The SOA record is managed separately, and the
+// records owned by nameservers are only tracked when they are managed.
+func trackedRecords(records []*api.DomainRecord, ownership map[string]string, includeNS bool) []*api.DomainRecord {
+	tracked := make([]*api.DomainRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.Type == api.SOAType || (!includeNS && api.RecordOwner(ownership, rec) == api.OwnerNameservers) {
+			continue
+		}
+		tracked = append(tracked, rec)
+	}
+	return tracked
+}
+
+// previousDomainRecords rebuilds the tracked records from the prior state of
+// a godaddy_domain_record
+func previousDomainRecords(d *schema.ResourceData) ([]*api.DomainRecord, bool) {
+	var records []*api.DomainRecord
+
+	addresses, _ := d.GetChange(attrAddresses)
+	for _, data := range addresses.([]interface{}) {
+		records = append(records, &api.DomainRecord{Type: api.AType, Name: api.Ptr, Data: data.(string), TTL: api.DefaultTTL})
+	}
+
+	nameservers, _ := d.GetChange(attrNameservers)
+	for _, data := range nameservers.([]interface{}) {
+		records = append(records, &api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: data.(string), TTL: api.DefaultTTL})
+	}
+
+	old, _ := d.GetChange(attrRecord)
+	for _, rec := range old.(*schema.Set).List() {
+		data := rec.(map[string]interface{})
+		port := data[recPort].(int)
+		records = append(records, &api.DomainRecord{
+			Type:     data[recType].(string),
+			Name:     data[recName].(string),
+			Data:     data[recData].(string),
+			TTL:      data[recTTL].(int),
+			Priority: data[recPriority].(int),
+			Weight:   data[recWeight].(int),
+			Port:     &port,
+			Service:  data[recService].(string),
+			Protocol: data[recProto].(string),
+		})
+	}
+	return records, len(nameservers.([]interface{})) > 0
+}
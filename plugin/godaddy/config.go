@@ -2,27 +2,69 @@ package godaddy
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/n3integration/terraform-provider-godaddy/api"
 )
 
+var logger = api.NewLogger("provider")
+
 // Config provides the provider's configuration
 type Config struct {
-	Key     string
-	Secret  string
-	BaseURL string
+	Key            string
+	Secret         string
+	BaseURL        string
+	LogBodies      bool
+	DefaultRecords []*api.DomainRecord
+	SnapshotDir    string
+	SnapshotFormat string
+	Safeguards     api.Safeguards
+	Resolver       *api.Resolver
+	Options        []api.ClientOpt
+}
+
+// providerMeta is supplied to each resource. It embeds the API client along
+// with the provider-level settings that apply to every resource.
+type providerMeta struct {
+	api.DNSClient
+	DefaultRecords []*api.DomainRecord
+	Safeguards     api.Safeguards
+	Resolver       *api.Resolver
+}
+
+// SnapshotOnce snapshots the records of a domain with the embedded client,
+// so that the client's snapshots are not hidden by the embedding
+func (m *providerMeta) SnapshotOnce(customerID, domain string) (api.DNSClient, error) {
+	return api.SnapshotOnce(m.DNSClient, customerID, domain)
 }
 
 // Client returns a new client for accessing GoDaddy.
 func (c *Config) Client() (*api.Client, error) {
-	client, err := api.NewClient(c.BaseURL, c.Key, c.Secret)
+	opts := []api.ClientOpt{api.WithBodyLogging(c.LogBodies)}
+	if c.SnapshotDir != "" {
+		opts = append(opts, api.WithSnapshots(c.SnapshotDir, c.SnapshotFormat))
+	}
+	opts = append(opts, c.Options...)
+	client, err := api.NewClient(c.BaseURL, c.Key, c.Secret, opts...)
 
 	if err != nil {
 		return nil, fmt.Errorf("error setting up client: %s", err)
 	}
 
-	log.Print("GoDaddy Client configured")
+	logger.Info("GoDaddy client configured", "baseurl", c.BaseURL, "log_bodies", c.LogBodies)
 
 	return client, nil
 }
+
+// Meta returns the client and provider settings supplied to each resource
+func (c *Config) Meta() (*providerMeta, error) {
+	client, err := c.Client()
+	if err != nil {
+		return nil, err
+	}
+	return &providerMeta{
+		DNSClient:      client,
+		DefaultRecords: c.DefaultRecords,
+		Safeguards:     c.Safeguards,
+		Resolver:       c.Resolver,
+	}, nil
+}
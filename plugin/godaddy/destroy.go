@@ -0,0 +1,228 @@
+package godaddy
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const (
+	attrOnDestroy = "on_destroy"
+	attrSnapshot  = "snapshot"
+
+	// onDestroyRestoreDefaults replaces the managed records with the default
+	// record set
+	onDestroyRestoreDefaults = "restore_defaults"
+	// onDestroyRestoreSnapshot restores the records captured when the
+	// resource was created or imported
+	onDestroyRestoreSnapshot = "restore_snapshot"
+	// onDestroyRemoveManaged deletes the managed records
+	onDestroyRemoveManaged = "remove_managed"
+	// onDestroyRetain leaves the records in place
+	onDestroyRetain = "retain"
+)
+
+func onDestroySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  onDestroyRestoreDefaults,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+			onDestroyRestoreDefaults,
+			onDestroyRestoreSnapshot,
+			onDestroyRemoveManaged,
+			onDestroyRetain,
+		}, false)),
+		Description: "What happens to the records when the resource is destroyed: restore_defaults, restore_snapshot, remove_managed or retain.",
+	}
+}
+
+func snapshotSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The zone's records when the resource was created or imported, one zone file entry per record.",
+	}
+}
+
+// captureSnapshot records the zone's current records, which are restored on
+// destroy when on_destroy is restore_snapshot. The records are returned for
+// evaluating the safeguards.
+func captureSnapshot(client api.DNSClient, customer, domain string, d *schema.ResourceData) ([]*api.DomainRecord, error) {
+	logger.Debug("capturing domain records snapshot", "domain", domain)
+	records, err := client.GetDomainRecords(customer, domain)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := zoneFileLines(records)
+	if err != nil {
+		return nil, err
+	}
+	return records, d.Set(attrSnapshot, lines)
+}
+
+// destroyRecords applies the on_destroy behavior to the managed records
+func destroyRecords(d *schema.ResourceData, meta interface{}, customer, domain string, managed []*api.DomainRecord) diag.Diagnostics {
+	client := meta.(api.DNSClient)
+
+	var target []*api.DomainRecord
+	switch d.Get(attrOnDestroy).(string) {
+	case onDestroyRetain:
+		logger.Info("retaining domain records", "domain", domain)
+		return nil
+	case onDestroyRemoveManaged:
+		logger.Info("removing managed domain records", "domain", domain)
+	case onDestroyRestoreSnapshot:
+		snapshot, err := parseSnapshot(domain, d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(snapshot) == 0 {
+			logger.Warn("no snapshot was captured; restoring default domain records", "domain", domain)
+			target = providerDefaultRecords(meta)
+			break
+		}
+		logger.Info("restoring domain records snapshot", "domain", domain)
+		target = snapshot
+	default:
+		logger.Info("restoring default domain records", "domain", domain)
+		target = providerDefaultRecords(meta)
+	}
+
+	remote, err := client.GetDomainRecords(customer, domain)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	after := remote
+	if len(target) > 0 {
+		after = api.AppliedRecords(remote, target)
+	}
+	deletions, err := managedDeletions(domain, after, managed, target)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if diags := checkDestroySafeguards(d, meta, domain, remote, withoutDeletions(domain, after, deletions)); diags.HasError() {
+		return diags
+	}
+
+	return diag.FromErr(restoreRecords(client, customer, domain, target, deletions))
+}
+
+// deletion identifies the records of a type and name that are deleted on
+// destroy
+type deletion struct {
+	t    string
+	name string
+}
+
+// managedDeletions returns the managed type and name pairs that remain in
+// records once target has been restored. The apex NS records cannot be
+// removed, so they are left in place unless target replaces them.
+func managedDeletions(domain string, records, managed, target []*api.DomainRecord) ([]deletion, error) {
+	restored := make(map[string]bool)
+	for _, rec := range target {
+		restored[strings.ToUpper(rec.Type)] = true
+	}
+
+	var deletions []deletion
+	seen := make(map[deletion]bool)
+	for _, rec := range managed {
+		t := strings.ToUpper(rec.Type)
+		name, err := api.NormalizeName(domain, rec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		del := deletion{t, name}
+		if restored[t] || seen[del] || t == api.SOAType || (t == api.NSType && name == api.Ptr) {
+			continue
+		}
+		if !hasRecord(domain, records, t, name) {
+			continue
+		}
+		seen[del] = true
+		deletions = append(deletions, del)
+	}
+	return deletions, nil
+}
+
+// withoutDeletions filters the records removed by the deletions
+func withoutDeletions(domain string, records []*api.DomainRecord, deletions []deletion) []*api.DomainRecord {
+	remaining := make([]*api.DomainRecord, 0, len(records))
+	for _, rec := range records {
+		deleted := false
+		for _, del := range deletions {
+			if strings.EqualFold(rec.Type, del.t) && api.EquivalentName(domain, rec.Name, del.name) {
+				deleted = true
+				break
+			}
+		}
+		if !deleted {
+			remaining = append(remaining, rec)
+		}
+	}
+	return remaining
+}
+
+// restoreRecords replaces the zone's records with target, as
+// UpdateDomainRecords does on apply, then deletes the remaining managed
+// records. A single snapshot is taken before any records are changed.
+func restoreRecords(client api.DNSClient, customer, domain string, target []*api.DomainRecord, deletions []deletion) error {
+	if len(target) == 0 && len(deletions) == 0 {
+		return nil
+	}
+	client, err := api.SnapshotOnce(client, customer, domain)
+	if err != nil {
+		return err
+	}
+
+	if len(target) > 0 {
+		if err := client.UpdateDomainRecords(customer, domain, target); err != nil {
+			return err
+		}
+	}
+
+	for _, del := range deletions {
+		logger.Debug("deleting managed domain records", "domain", domain, "type", del.t, "name", del.name)
+		if err := client.DeleteDomainRecords(customer, domain, del.t, del.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseSnapshot(domain string, d *schema.ResourceData) ([]*api.DomainRecord, error) {
+	lines := d.Get(attrSnapshot).([]interface{})
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	content := make([]string, len(lines))
+	for i, line := range lines {
+		content[i] = line.(string)
+	}
+	return parseZoneFile(domain, strings.Join(content, "\n"))
+}
+
+// providerDefaultRecords returns the default record set configured for the
+// provider, falling back to GoDaddy's defaults
+func providerDefaultRecords(meta interface{}) []*api.DomainRecord {
+	if m, ok := meta.(*providerMeta); ok && len(m.DefaultRecords) > 0 {
+		return m.DefaultRecords
+	}
+	return defaultRecords
+}
+
+func hasRecord(domain string, records []*api.DomainRecord, t, name string) bool {
+	for _, rec := range records {
+		if strings.EqualFold(rec.Type, t) && api.EquivalentName(domain, rec.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package godaddy
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+// domainLocks serializes the read-modify-write cycles of resources that
+// target the same domain, which Terraform would otherwise apply in parallel
+var domainLocks = newMutexKV()
+
+// mutexKV is a set of mutexes keyed by name
+type mutexKV struct {
+	sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+func newMutexKV() *mutexKV {
+	return &mutexKV{store: make(map[string]*sync.Mutex)}
+}
+
+// Lock acquires the mutex for the key, creating it if necessary
+func (m *mutexKV) Lock(key string) {
+	m.get(key).Lock()
+}
+
+// Unlock releases the mutex for the key
+func (m *mutexKV) Unlock(key string) {
+	m.get(key).Unlock()
+}
+
+func (m *mutexKV) get(key string) *sync.Mutex {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	mu, ok := m.store[key]
+	if !ok {
+		mu = &sync.Mutex{}
+		m.store[key] = mu
+	}
+	return mu
+}
+
+// lockDomain acquires the lock for a customer's domain, returning a function
+// that releases it
+func lockDomain(customer, domain string) func() {
+	key := customer + "/" + domainKey(domain)
+	logger.Debug("acquiring domain lock", "key", key)
+	domainLocks.Lock(key)
+	return func() {
+		domainLocks.Unlock(key)
+	}
+}
+
+// domainKey canonicalizes a domain so that every spelling of it shares a lock
+func domainKey(domain string) string {
+	domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+	if ascii, err := api.ToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
@@ -0,0 +1,40 @@
+package godaddy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockDomain(t *testing.T) {
+	unlock := lockDomain("", "Example.COM.")
+
+	acquired := make(chan struct{})
+	go func() {
+		defer lockDomain("", "example.com")()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected the same domain to be serialized")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	other := make(chan struct{})
+	go func() {
+		defer lockDomain("1234", "example.com")()
+		close(other)
+	}()
+	select {
+	case <-other:
+	case <-time.After(time.Second):
+		t.Fatal("expected another customer's domain to be locked independently")
+	}
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("expected the lock to be released")
+	}
+}
@@ -0,0 +1,120 @@
+package godaddy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const attrAllowNameserverChange = "allow_nameserver_change"
+
+func allowNameserverChangeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Confirm changes to the apex NS records, which can take the domain offline if they are wrong.",
+	}
+}
+
+// planDomainRecordNameservers confirms changes to the apex NS records of the
+// planned nameservers and records
+func planDomainRecordNameservers(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(attrDomain) || !d.NewValueKnown(attrRecord) ||
+		!d.NewValueKnown(attrAddresses) || !d.NewValueKnown(attrNameservers) {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange(attrNameservers) && !d.HasChange(attrRecord) {
+		return nil
+	}
+	return planNameserverChange(d, meta, d.Get(attrDomain).(string), plannedRecords(d))
+}
+
+// planNameserverChange fails the plan when the planned records would change
+// the apex NS records without allow_nameserver_change. The remote records are
+// only fetched when the planned records contain NS records, and the new
+// nameservers are only checked when the change is applied.
+func planNameserverChange(d *schema.ResourceDiff, meta interface{}, domain string, planned []*api.DomainRecord) error {
+	if d.Get(attrAllowNameserverChange).(bool) || !hasNSRecords(planned) {
+		return nil
+	}
+	client, ok := meta.(api.DNSClient)
+	if !ok || !d.NewValueKnown(attrCustomer) {
+		return nil
+	}
+
+	current, err := client.GetDomainRecords(d.Get(attrCustomer).(string), domain)
+	if err != nil {
+		return fmt.Errorf("couldn't find domain record (%s): %s", domain, err)
+	}
+	if before, after, changed := nameserverChange(domain, current, planned); changed {
+		return nameserverChangeError(domain, before, after)
+	}
+	return nil
+}
+
+// checkNameserverChange guards the apex NS records when they are applied.
+// Changing them requires allow_nameserver_change, which is also checked here
+// in case the plan could not tell, and each new nameserver must resolve and
+// answer authoritatively for the domain before the zone is delegated to it.
+func checkNameserverChange(ctx context.Context, d *schema.ResourceData, meta interface{}, domain string, current, desired []*api.DomainRecord) error {
+	before, after, changed := nameserverChange(domain, current, desired)
+	if !changed {
+		return nil
+	}
+
+	if !d.Get(attrAllowNameserverChange).(bool) {
+		return nameserverChangeError(domain, before, after)
+	}
+
+	existing := make(map[string]bool)
+	for _, ns := range before {
+		existing[ns] = true
+	}
+	var added []string
+	for _, ns := range after {
+		if !existing[ns] {
+			added = append(added, ns)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	logger.Info("checking nameservers", "domain", domain, "nameservers", added)
+	return providerResolver(meta).CheckNameservers(ctx, domain, added)
+}
+
+// nameserverChange compares the apex nameservers before and after
+// UpdateDomainRecords replaces the current records with desired
+func nameserverChange(domain string, current, desired []*api.DomainRecord) (before, after []string, changed bool) {
+	before = api.ApexNameservers(domain, api.ReplacedRecords(current, desired))
+	after = api.ApexNameservers(domain, api.ReplacedRecords(desired, desired))
+	return before, after, strings.Join(before, ",") != strings.Join(after, ",")
+}
+
+func nameserverChangeError(domain string, before, after []string) error {
+	return fmt.Errorf("changing the nameservers of %s from [%s] to [%s] can take the domain offline; set %s = true to confirm the change",
+		domain, strings.Join(before, ", "), strings.Join(after, ", "), attrAllowNameserverChange)
+}
+
+func hasNSRecords(records []*api.DomainRecord) bool {
+	for _, rec := range records {
+		if strings.EqualFold(rec.Type, api.NSType) {
+			return true
+		}
+	}
+	return false
+}
+
+// providerResolver returns the resolver configured for the provider, falling
+// back to the system resolver
+func providerResolver(meta interface{}) *api.Resolver {
+	if m, ok := meta.(*providerMeta); ok && m.Resolver != nil {
+		return m.Resolver
+	}
+	return &api.Resolver{}
+}
@@ -0,0 +1,76 @@
+package godaddy
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const attrOwnership = "ownership"
+
+func ownershipSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The attribute (addresses, nameservers or record) that each apex A and NS record is stored in.",
+	}
+}
+
+// planOwnership marks the ownership as unknown whenever the records that
+// determine it change
+func planOwnership(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	for _, attr := range []string{attrAddresses, attrNameservers, attrRecord} {
+		if d.HasChange(attr) {
+			return d.SetNewComputed(attrOwnership)
+		}
+	}
+	return nil
+}
+
+// ownership maps each configured apex A and NS record to the attribute it
+// was declared in. It must be called before converge, which merges the
+// addresses and nameservers into the record set.
+func (r *domainRecordResource) ownership() map[string]string {
+	owners := make(map[string]string)
+	for _, rec := range r.Records {
+		name, err := api.NormalizeName(r.Domain, rec.Name)
+		if err != nil {
+			continue
+		}
+		owned := *rec
+		owned.Name = name
+		if key := api.OwnershipKey(&owned); key != "" {
+			owners[key] = api.OwnerRecord
+		}
+	}
+	for _, data := range r.ARecords {
+		owners[api.OwnershipKey(&api.DomainRecord{Type: api.AType, Name: api.Ptr, Data: data})] = api.OwnerAddresses
+	}
+	for _, data := range r.NSRecords {
+		owners[api.OwnershipKey(&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: data})] = api.OwnerNameservers
+	}
+	return owners
+}
+
+// fetchedOwnership records the owner of each fetched apex A and NS record, so
+// that records placed by the fallback heuristic keep their place
+func fetchedOwnership(records []*api.DomainRecord, ownership map[string]string) map[string]string {
+	owners := make(map[string]string)
+	for _, rec := range records {
+		if key := api.OwnershipKey(rec); key != "" {
+			owners[key] = api.RecordOwner(ownership, rec)
+		}
+	}
+	return owners
+}
+
+// stateOwnership reads an ownership map from the resource data
+func stateOwnership(v interface{}) map[string]string {
+	owners := make(map[string]string)
+	for key, owner := range v.(map[string]interface{}) {
+		owners[key] = owner.(string)
+	}
+	return owners
+}
@@ -1,12 +1,18 @@
 package godaddy
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/n3integration/terraform-provider-godaddy/api"
 )
 
+const attrDefaultRecord = "default_record"
+
 // Provider returns a terraform.ResourceProvider.
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"key": {
 				Type:        schema.TypeString,
@@ -28,22 +34,136 @@ func Provider() *schema.Provider {
 				Default:     "https://api.godaddy.com",
 				Description: "GoDaddy Base Url(defaults to production).",
 			},
+
+			"log_bodies": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GODADDY_LOG_BODIES", false),
+				Description: "Log API request and response bodies at the DEBUG level (contact details are redacted).",
+			},
+
+			"snapshot_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GODADDY_SNAPSHOT_DIR", ""),
+				Description: "Directory in which to snapshot a domain's records before they are replaced. Snapshots may be restored with `godaddy-dns restore`.",
+			},
+
+			"snapshot_format": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.SnapshotJSON,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{api.SnapshotJSON, api.SnapshotZone}, false)),
+				Description:      "Snapshot file format: json or zone.",
+			},
+
+			"resolver": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GODADDY_RESOLVER", ""),
+				Description: "The host:port of the DNS resolver used to look up nameservers before the apex NS records are changed (defaults to the system resolver).",
+			},
+
+			"nameserver_port": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          api.DefaultNameserverPort,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+				Description:      "The port on which nameservers are queried to confirm that they serve a domain.",
+			},
+
+			attrDefaultRecord: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Records restored when a resource with on_destroy = \"restore_defaults\" is destroyed (defaults to GoDaddy's www and _domainconnect CNAMEs).",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						recName: {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						recType: {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateRecordType,
+						},
+						recData: {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						recTTL: {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  api.DefaultTTL,
+						},
+						recPriority: {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  api.DefaultPriority,
+						},
+					},
+				},
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"godaddy_domain_record": resourceDomainRecord(),
+			"godaddy_zone_file":     resourceZoneFile(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureFunc: providerConfigure(),
 	}
+
+	for k, v := range safeguardSchemas("disables the limit") {
+		p.Schema[k] = v
+	}
+	return p
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	config := Config{
-		Key:     d.Get("key").(string),
-		Secret:  d.Get("secret").(string),
-		BaseURL: d.Get("baseurl").(string),
+// providerConfigure returns a schema.ConfigureFunc that constructs the API
+// client with any additional client options applied.
+func providerConfigure(opts ...api.ClientOpt) schema.ConfigureFunc {
+	return func(d *schema.ResourceData) (interface{}, error) {
+		defaults, err := expandDefaultRecords(d.Get(attrDefaultRecord).([]interface{}))
+		if err != nil {
+			return nil, err
+		}
+
+		config := Config{
+			Key:            d.Get("key").(string),
+			Secret:         d.Get("secret").(string),
+			BaseURL:        d.Get("baseurl").(string),
+			LogBodies:      d.Get("log_bodies").(bool),
+			DefaultRecords: defaults,
+			SnapshotDir:    d.Get("snapshot_dir").(string),
+			SnapshotFormat: d.Get("snapshot_format").(string),
+			Safeguards:     expandSafeguards(d),
+			Resolver: &api.Resolver{
+				Addr: d.Get("resolver").(string),
+				Port: d.Get("nameserver_port").(int),
+			},
+			Options: opts,
+		}
+
+		return config.Meta()
 	}
+}
 
-	return config.Client()
+// expandDefaultRecords converts the default_record blocks into records
+func expandDefaultRecords(list []interface{}) ([]*api.DomainRecord, error) {
+	records := make([]*api.DomainRecord, len(list))
+	for i, item := range list {
+		data := item.(map[string]interface{})
+		rec, err := api.NewDomainRecord(
+			data[recName].(string),
+			data[recType].(string),
+			data[recData].(string),
+			data[recTTL].(int),
+			api.Priority(data[recPriority].(int)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", attrDefaultRecord, err)
+		}
+		records[i] = rec
+	}
+	return records, nil
 }
@@ -3,9 +3,12 @@ package godaddy
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/n3integration/terraform-provider-godaddy/api/godaddytest"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -35,3 +38,44 @@ func verifyEnvExists(t *testing.T, key string) {
 		t.Fatal(fmt.Sprintf("%s must be set for acceptance tests.", key))
 	}
 }
+
+// testAccProviderFactories serves providers whose clients are not rate
+// limited, for use against a local godaddytest.Server
+var testAccProviderFactories = map[string]func() (*schema.Provider, error){
+	"godaddy": func() (*schema.Provider, error) {
+		p := Provider()
+		p.ConfigureFunc = providerConfigure(api.WithRateLimiter(nil))
+		return p, nil
+	},
+}
+
+// testAccProviderConfig points the provider at a local godaddytest.Server
+func testAccProviderConfig(server *godaddytest.Server) string {
+	return fmt.Sprintf(`
+provider "godaddy" {
+  key     = %q
+  secret  = %q
+  baseurl = %q
+}
+`, server.Key, server.Secret, server.URL)
+}
+
+// newTestAccDNSServer starts a local DNS server that resolves the hosts and
+// answers authoritatively for the test domain
+func newTestAccDNSServer(t *testing.T, hosts ...string) *godaddytest.DNSServer {
+	dns := godaddytest.NewDNSServer()
+	t.Cleanup(dns.Close)
+
+	for _, host := range hosts {
+		dns.AddHost(host)
+	}
+	dns.AddZone(testDomain)
+	return dns
+}
+
+// testAccResolverConfig points the provider at a local godaddytest.Server,
+// checking nameservers against a local godaddytest.DNSServer
+func testAccResolverConfig(server *godaddytest.Server, dns *godaddytest.DNSServer) string {
+	return strings.Replace(testAccProviderConfig(server), "}\n",
+		fmt.Sprintf("  resolver        = %q\n  nameserver_port = %d\n}\n", dns.Addr, dns.Port()), 1)
+}
@@ -3,11 +3,11 @@ package godaddy
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/n3integration/terraform-provider-godaddy/api"
 )
@@ -15,9 +15,11 @@ import (
 const (
 	attrCustomer    = "customer"
 	attrDomain      = "domain"
+	attrUnicode     = "domain_unicode"
 	attrRecord      = "record"
 	attrAddresses   = "addresses"
 	attrNameservers = "nameservers"
+	attrSOA         = "soa"
 
 	recName     = "name"
 	recType     = "type"
@@ -28,6 +30,14 @@ const (
 	recProto    = "protocol"
 	recService  = "service"
 	recPort     = "port"
+
+	soaMName   = "mname"
+	soaRName   = "rname"
+	soaSerial  = "serial"
+	soaRefresh = "refresh"
+	soaRetry   = "retry"
+	soaExpire  = "expire"
+	soaMinimum = "minimum"
 )
 
 type domainRecordResource struct {
@@ -37,6 +47,7 @@ type domainRecordResource struct {
 	ARecords         []string
 	NSRecords        []string
 	ReplaceNSRecords bool
+	SOA              *api.SOAData
 }
 
 var defaultRecords = []*api.DomainRecord{
@@ -108,6 +119,18 @@ func newDomainRecordResource(d *schema.ResourceData) (*domainRecordResource, err
 		}
 	}
 
+	if attr, ok := d.GetOk(attrSOA); ok {
+		if list := attr.([]interface{}); len(list) > 0 && list[0] != nil {
+			data := list[0].(map[string]interface{})
+			r.SOA = &api.SOAData{
+				Refresh: data[soaRefresh].(int),
+				Retry:   data[soaRetry].(int),
+				Expire:  data[soaExpire].(int),
+				Minimum: data[soaMinimum].(int),
+			}
+		}
+	}
+
 	if nsCount > 0 {
 		r.ReplaceNSRecords = true
 	}
@@ -128,11 +151,12 @@ func (r *domainRecordResource) mergeRecords(list []string, factory api.RecordFac
 }
 
 func resourceDomainRecord() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: resourceDomainRecordCreate,
 		ReadContext:   resourceDomainRecordRead,
 		UpdateContext: resourceDomainRecordUpdate,
 		DeleteContext: resourceDomainRecordRestore,
+		CustomizeDiff: customdiff.Sequence(validateRecords, lintRecords, planDomainRecordNameservers, planOwnership),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -147,16 +171,26 @@ func resourceDomainRecord() *schema.Resource {
 			attrAddresses: {
 				Type:     schema.TypeList,
 				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateRecordData(api.AType),
+				},
 			},
 			attrCustomer: {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			attrOnDestroy:             onDestroySchema(),
+			attrForce:                 forceSchema(),
+			attrOverrideSafeguards:    overrideSafeguardsSchema(),
+			attrAllowNameserverChange: allowNameserverChangeSchema(),
 			attrNameservers: {
 				Type:     schema.TypeList,
 				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateRecordData(api.NSType),
+				},
 			},
 			attrRecord: {
 				Type:     schema.TypeSet,
@@ -168,8 +202,9 @@ func resourceDomainRecord() *schema.Resource {
 							Required: true,
 						},
 						recType: {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateRecordType,
 						},
 						recData: {
 							Type:     schema.TypeString,
@@ -206,12 +241,66 @@ func resourceDomainRecord() *schema.Resource {
 					},
 				},
 			},
+			// Computed
+			attrUnicode:    unicodeSchema(),
+			attrSnapshot:   snapshotSchema(),
+			attrRemoteHash: remoteHashSchema(),
+			attrOwnership:  ownershipSchema(),
+			attrSOA: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Description: "The zone's SOA record. The mname, rname and serial are read-only; the timers may be overridden.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						soaMName: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						soaRName: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						soaSerial: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						soaRefresh: soaTimerSchema(),
+						soaRetry:   soaTimerSchema(),
+						soaExpire:  soaTimerSchema(),
+						soaMinimum: soaTimerSchema(),
+					},
+				},
+			},
 		},
 	}
+
+	for k, v := range safeguardSchemas("uses the provider setting") {
+		r.Schema[k] = v
+	}
+	return r
+}
+
+func unicodeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The domain name in its Unicode form, for internationalized domain names.",
+	}
+}
+
+func soaTimerSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeInt,
+		Optional:         true,
+		Computed:         true,
+		ValidateDiagFunc: validateSOATimer,
+	}
 }
 
 func resourceDomainRecordRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*api.Client)
+	client := meta.(api.DNSClient)
 	customer := d.Get(attrCustomer).(string)
 	domain := d.Get(attrDomain).(string)
 	r, err := newDomainRecordResource(d)
@@ -223,9 +312,16 @@ func resourceDomainRecordRead(_ context.Context, d *schema.ResourceData, meta in
 	if domain == "" {
 		r.Domain = d.Id()
 		domain = r.Domain
+		if _, err := captureSnapshot(client, customer, domain, d); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set(attrOnDestroy, onDestroyRestoreDefaults)
+		d.Set(attrForce, false)
+		d.Set(attrOverrideSafeguards, false)
+		d.Set(attrAllowNameserverChange, false)
 	}
 
-	log.Println("Fetching", domain, "records...")
+	logger.Debug("fetching domain records", "domain", domain)
 	records, err := client.GetDomainRecords(customer, domain)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("couldn't find domain record (%s): %s", domain, err.Error()))
@@ -242,8 +338,9 @@ func resourceDomainRecordRead(_ context.Context, d *schema.ResourceData, meta in
 	return nil
 }
 
-func resourceDomainRecordCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*api.Client)
+func resourceDomainRecordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	defer lockDomain(d.Get(attrCustomer).(string), d.Get(attrDomain).(string))()
+	client := meta.(api.DNSClient)
 	r, err := newDomainRecordResource(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -253,16 +350,33 @@ func resourceDomainRecordCreate(_ context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	log.Println("Creating", r.Domain, "domain records...")
+	remote, err := captureSnapshot(client, r.Customer, r.Domain, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info("creating domain records", "domain", r.Domain)
+	d.Set(attrOwnership, r.ownership())
 	r.converge()
-	if err := client.UpdateDomainRecords(r.Customer, r.Domain, r.Records); err != nil {
+	soa, err := soaTimerUpdate(d, remote, r)
+	if err != nil {
 		return diag.FromErr(err)
 	}
-	return nil
+	if diags := checkSafeguards(d, meta, r.Domain, remote, r.Records); diags.HasError() {
+		return diags
+	}
+	if err := checkNameserverChange(ctx, d, meta, r.Domain, remote, r.Records); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := applyDomainRecords(client, r, soa); err != nil {
+		return diag.FromErr(err)
+	}
+	return append(lintWarnings(r.Domain, r.Records), resourceDomainRecordRead(ctx, d, meta)...)
 }
 
-func resourceDomainRecordUpdate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*api.Client)
+func resourceDomainRecordUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	defer lockDomain(d.Get(attrCustomer).(string), d.Get(attrDomain).(string))()
+	client := meta.(api.DNSClient)
 	r, err := newDomainRecordResource(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -272,16 +386,38 @@ func resourceDomainRecordUpdate(_ context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	log.Println("Updating", r.Domain, "domain records...")
+	remote, err := client.GetDomainRecords(r.Customer, r.Domain)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	previous, includeNS := previousDomainRecords(d)
+	ownership, _ := d.GetChange(attrOwnership)
+	if err := checkRemoteUnchanged(d, r.Domain, previous, trackedRecords(remote, stateOwnership(ownership), includeNS)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info("updating domain records", "domain", r.Domain)
+	d.Set(attrOwnership, r.ownership())
 	r.converge()
-	if err := client.UpdateDomainRecords(r.Customer, r.Domain, r.Records); err != nil {
+	soa, err := soaTimerUpdate(d, remote, r)
+	if err != nil {
 		return diag.FromErr(err)
 	}
-	return nil
+	if diags := checkSafeguards(d, meta, r.Domain, remote, r.Records); diags.HasError() {
+		return diags
+	}
+	if err := checkNameserverChange(ctx, d, meta, r.Domain, remote, r.Records); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := applyDomainRecords(client, r, soa); err != nil {
+		return diag.FromErr(err)
+	}
+	return append(lintWarnings(r.Domain, r.Records), resourceDomainRecordRead(ctx, d, meta)...)
 }
 
 func resourceDomainRecordRestore(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*api.Client)
+	defer lockDomain(d.Get(attrCustomer).(string), d.Get(attrDomain).(string))()
+	client := meta.(api.DNSClient)
 	r, err := newDomainRecordResource(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -291,55 +427,51 @@ func resourceDomainRecordRestore(_ context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	log.Println("Restoring", r.Domain, "domain records...")
-	if err := client.UpdateDomainRecords(r.Customer, r.Domain, defaultRecords); err != nil {
-		return diag.FromErr(err)
-	}
-	return nil
+	r.converge()
+	return destroyRecords(d, meta, r.Customer, r.Domain, r.Records)
 }
 
-func populateDomainInfo(client *api.Client, r *domainRecordResource, d *schema.ResourceData) error {
+func populateDomainInfo(client api.DNSClient, r *domainRecordResource, d *schema.ResourceData) error {
 	var err error
 	var domain *api.Domain
 
-	log.Println("Fetching", r.Domain, "info...")
+	logger.Debug("fetching domain info", "domain", r.Domain)
 	domain, err = client.GetDomain(r.Customer, r.Domain)
 	if err != nil {
 		return fmt.Errorf("couldn't find domain (%s): %s", r.Domain, err.Error())
 	}
 
 	d.SetId(strconv.FormatInt(domain.ID, 10))
-	return nil
+	return d.Set(attrUnicode, api.ToUnicode(domain.Name))
 }
 
 func populateResourceDataFromResponse(recs []*api.DomainRecord, r *domainRecordResource, d *schema.ResourceData) error {
-	aRecords := make([]string, 0)
-	nsRecords := make([]string, 0)
 	domain := d.Get(attrDomain).(string)
-	records := make([]*api.DomainRecord, 0)
-
-	for _, rec := range recs {
-		switch {
-		case api.IsDefaultNSRecord(rec):
-			nsRecords = append(nsRecords, rec.Data)
-		case api.IsDefaultARecord(rec):
-			aRecords = append(aRecords, rec.Data)
-		default:
-			records = append(records, rec)
-		}
-	}
+	ownership := stateOwnership(d.Get(attrOwnership))
+	aRecords, nsRecords, records := api.PartitionOwnedRecords(recs, ownership)
 
 	if err := d.Set(attrAddresses, aRecords); err != nil {
 		return err
 	}
 
-	if r.ReplaceNSRecords || domain == "" {
+	includeNS := r.ReplaceNSRecords || domain == ""
+	if includeNS {
 		if err := d.Set(attrNameservers, nsRecords); err != nil {
 			return err
 		}
 	}
+	if err := d.Set(attrRemoteHash, api.HashRecords(r.Domain, trackedRecords(recs, ownership, includeNS))); err != nil {
+		return err
+	}
+	if err := d.Set(attrOwnership, fetchedOwnership(recs, ownership)); err != nil {
+		return err
+	}
 
-	if err := d.Set(attrRecord, flattenRecords(records)); err != nil {
+	if err := d.Set(attrRecord, flattenRecords(preserveConfiguredData(r.Domain, records, r.Records))); err != nil {
+		return err
+	}
+
+	if err := d.Set(attrSOA, flattenSOA(recs)); err != nil {
 		return err
 	}
 
@@ -350,6 +482,117 @@ func populateResourceDataFromResponse(recs []*api.DomainRecord, r *domainRecordR
 	return nil
 }
 
+// applyDomainRecords replaces the records of the domain, then updates the
+// SOA timers, if any. A single snapshot is taken before either is written.
+func applyDomainRecords(client api.DNSClient, r *domainRecordResource, soa *api.DomainRecord) error {
+	client, err := api.SnapshotOnce(client, r.Customer, r.Domain)
+	if err != nil {
+		return err
+	}
+	if err := client.UpdateDomainRecords(r.Customer, r.Domain, r.Records); err != nil {
+		return err
+	}
+	if soa == nil {
+		return nil
+	}
+
+	logger.Info("updating SOA timers", "domain", r.Domain)
+	return client.SetDomainRecords(r.Customer, r.Domain, api.SOAType, soa.Name, []*api.DomainRecord{soa})
+}
+
+// soaTimerUpdate returns the zone's SOA record with the configured timers
+// applied, or nil when they are unchanged. UpdateDomainRecords never writes
+// the SOA record, so only its timers are sent, separately; the mname, rname
+// and serial are managed by GoDaddy and are sent back as they were read.
+func soaTimerUpdate(d *schema.ResourceData, records []*api.DomainRecord, r *domainRecordResource) (*api.DomainRecord, error) {
+	if r.SOA == nil || !d.HasChange(attrSOA) {
+		return nil, nil
+	}
+
+	rec := findSOARecord(records)
+	if rec == nil {
+		return nil, fmt.Errorf("domain %s has no SOA record to update", r.Domain)
+	}
+	current, err := api.ParseSOA(rec.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := *current
+	for _, timer := range []struct{ configured, desired *int }{
+		{&r.SOA.Refresh, &desired.Refresh},
+		{&r.SOA.Retry, &desired.Retry},
+		{&r.SOA.Expire, &desired.Expire},
+		{&r.SOA.Minimum, &desired.Minimum},
+	} {
+		if *timer.configured > 0 {
+			*timer.desired = *timer.configured
+		}
+	}
+	if desired == *current {
+		return nil, nil
+	}
+
+	updated := *rec
+	updated.Data = desired.String()
+	return &updated, nil
+}
+
+func findSOARecord(records []*api.DomainRecord) *api.DomainRecord {
+	for _, rec := range records {
+		if rec.Type == api.SOAType {
+			return rec
+		}
+	}
+	return nil
+}
+
+// flattenSOA returns the soa attribute for the zone's SOA record, if any
+func flattenSOA(records []*api.DomainRecord) []map[string]interface{} {
+	rec := findSOARecord(records)
+	if rec == nil {
+		return nil
+	}
+
+	soa, err := api.ParseSOA(rec.Data)
+	if err != nil {
+		logger.Warn("ignoring unparseable SOA record", "data", rec.Data, "error", err)
+		return nil
+	}
+	return []map[string]interface{}{{
+		soaMName:   soa.MName,
+		soaRName:   soa.RName,
+		soaSerial:  int(soa.Serial),
+		soaRefresh: soa.Refresh,
+		soaRetry:   soa.Retry,
+		soaExpire:  soa.Expire,
+		soaMinimum: soa.Minimum,
+	}}
+}
+
+// preserveConfiguredData keeps the configured spelling of remote record names
+// and data that are semantically equal, so that presentation differences
+// (e.g. fully qualified names, TXT chunking or CAA value quoting) are not
+// reported as drift. Any other remote data is normalized.
+func preserveConfiguredData(domain string, remote, configured []*api.DomainRecord) []*api.DomainRecord {
+	result := make([]*api.DomainRecord, len(remote))
+	for i, rec := range remote {
+		normalized := *rec
+		normalized.Data = api.NormalizeData(rec.Type, rec.Data)
+		result[i] = &normalized
+		for _, c := range configured {
+			if c.Type == rec.Type && api.EquivalentName(domain, c.Name, rec.Name) && api.EquivalentData(rec.Type, rec.Data, c.Data) {
+				preserved := *rec
+				preserved.Name = c.Name
+				preserved.Data = c.Data
+				result[i] = &preserved
+				break
+			}
+		}
+	}
+	return result
+}
+
 func flattenRecords(list []*api.DomainRecord) []map[string]interface{} {
 	result := make([]map[string]interface{}, len(list))
 	for i, r := range list {
@@ -0,0 +1,1260 @@
+package godaddy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/n3integration/terraform-provider-godaddy/api/godaddytest"
+)
+
+const testDomain = "example.com"
+
+func newTestMemoryClient(records ...*api.DomainRecord) *api.MemoryClient {
+	client := api.NewMemoryClient()
+	client.AddDomain("", api.Domain{ID: 42, Name: testDomain}, records...)
+	return client
+}
+
+func TestResourceDomainRecordCreate(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.TXTType, Name: "stale", Data: "remove me", TTL: api.DefaultTTL},
+	)
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain:    testDomain,
+		attrAddresses: []interface{}{"192.168.1.2"},
+		attrRecord: []interface{}{
+			map[string]interface{}{recName: "www", recType: api.CNameType, recData: "@"},
+		},
+	})
+
+	if diags := resourceDomainRecordCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "42" {
+		t.Errorf("expected id to be the domain id, got %q", d.Id())
+	}
+
+	records, _ := client.GetDomainRecords("", testDomain)
+	counts := make(map[string]int)
+	for _, rec := range records {
+		counts[rec.Type]++
+	}
+	if counts[api.AType] != 1 || counts[api.CNameType] != 1 || counts[api.NSType] != 1 || counts[api.TXTType] != 0 {
+		t.Errorf("unexpected records after create: %v", counts)
+	}
+}
+
+func TestResourceDomainRecordCreateLintWarnings(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+	)
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain: testDomain,
+		attrRecord: []interface{}{
+			map[string]interface{}{recName: "mail", recType: api.CNameType, recData: "mail.example.net"},
+			map[string]interface{}{recName: api.Ptr, recType: api.MXType, recData: "mail.example.com", recPriority: 10},
+		},
+	})
+
+	diags := resourceDomainRecordCreate(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "is a CNAME") {
+		t.Errorf("expected a lint warning, got %v", diags)
+	}
+}
+
+func TestResourceDomainRecordRestoreSafeguards(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.AType, Name: "api", Data: "10.0.0.1", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.AType, Name: "api", Data: "10.0.0.2", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.MXType, Name: api.Ptr, Data: "mail.example.net", TTL: api.DefaultTTL},
+	)
+	meta := &providerMeta{DNSClient: client, Safeguards: api.Safeguards{MaxRecordsDeleted: 1}}
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain:    testDomain,
+		attrOnDestroy: onDestroyRemoveManaged,
+		attrRecord: []interface{}{
+			map[string]interface{}{recName: "api", recType: api.AType, recData: "10.0.0.1"},
+			map[string]interface{}{recName: "api", recType: api.AType, recData: "10.0.0.2"},
+		},
+	})
+
+	diags := resourceDomainRecordRestore(context.Background(), d, meta)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "2 records would be deleted, exceeding the limit of 1") {
+		t.Fatalf("expected the safeguards to refuse the deletions, got %v", diags)
+	}
+	if records, _ := client.GetDomainRecords("", testDomain); len(records) != 4 {
+		t.Errorf("expected the records to be retained, got %d", len(records))
+	}
+
+	d.Set(attrOverrideSafeguards, true)
+	if diags := resourceDomainRecordRestore(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if records, _ := client.GetDomainRecords("", testDomain); len(records) != 2 {
+		t.Errorf("expected the managed records to be deleted, got %d", len(records))
+	}
+}
+
+func TestResourceDomainRecordRestoreSnapshot(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain(testDomain,
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.AType, Name: "api", Data: "10.0.0.1", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.MXType, Name: api.Ptr, Data: "mail.example.net", TTL: api.DefaultTTL},
+	)
+
+	dir := t.TempDir()
+	client, err := server.Client(api.WithSnapshots(dir, api.SnapshotJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain:    testDomain,
+		attrOnDestroy: onDestroyRemoveManaged,
+		attrRecord: []interface{}{
+			map[string]interface{}{recName: "api", recType: api.AType, recData: "10.0.0.1"},
+			map[string]interface{}{recName: api.Ptr, recType: api.MXType, recData: "mail.example.net"},
+		},
+	})
+
+	if diags := resourceDomainRecordRestore(context.Background(), d, &providerMeta{DNSClient: client}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if records := server.Records(testDomain); len(records) != 1 {
+		t.Errorf("expected the managed records to be deleted, got %v", records)
+	}
+
+	snapshots, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(snapshots) != 1 {
+		t.Fatalf("expected a single snapshot before the records were removed, got %d", len(snapshots))
+	}
+	snapshot, err := api.ReadSnapshot(snapshots[0], testDomain)
+	if err != nil || len(snapshot) != 3 {
+		t.Errorf("expected the snapshot to hold the 3 records, got %v (%v)", snapshot, err)
+	}
+}
+
+func TestResourceDomainRecordCreateSOATimers(t *testing.T) {
+	server := godaddytest.NewServer()
+	defer server.Close()
+	server.AddDomain(testDomain,
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.SOAType, Name: api.Ptr, Data: "ns51.domaincontrol.com. dns.jomax.net. 2023071801 28800 7200 604800 600", TTL: api.DefaultTTL},
+	)
+
+	dir := t.TempDir()
+	client, err := server.Client(api.WithSnapshots(dir, api.SnapshotJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain: testDomain,
+		attrRecord: []interface{}{
+			map[string]interface{}{recName: api.Ptr, recType: api.TXTType, recData: "managed"},
+		},
+		attrSOA: []interface{}{
+			map[string]interface{}{soaRefresh: 3600, soaMinimum: 1800},
+		},
+	})
+
+	if diags := resourceDomainRecordCreate(context.Background(), d, &providerMeta{DNSClient: client}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// only the timers of the SOA record that was read are sent
+	var soaPuts []godaddytest.Request
+	for _, req := range server.Requests() {
+		if req.Method == http.MethodPut && req.Path == "/v1/domains/example.com/records/SOA/@" {
+			soaPuts = append(soaPuts, req)
+		}
+	}
+	if len(soaPuts) != 1 || !strings.Contains(soaPuts[0].Body, `"ns51.domaincontrol.com. dns.jomax.net. 2023071801 3600 7200 604800 1800"`) {
+		t.Errorf("expected a single SOA update of the timers, got %+v", soaPuts)
+	}
+
+	snapshots, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(snapshots) != 1 {
+		t.Errorf("expected a single snapshot before the records and SOA timers were written, got %d", len(snapshots))
+	}
+}
+
+func TestResourceDomainRecordRead(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.AType, Name: api.Ptr, Data: "192.168.1.2", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.MXType, Name: api.Ptr, Data: "mail.example.com", TTL: 600, Priority: 10},
+	)
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{})
+	d.SetId(testDomain)
+
+	if diags := resourceDomainRecordRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got := d.Get(attrDomain).(string); got != testDomain {
+		t.Errorf("expected imported domain, got %q", got)
+	}
+	if got := d.Get(attrAddresses).([]interface{}); len(got) != 1 || got[0] != "192.168.1.2" {
+		t.Errorf("unexpected addresses: %v", got)
+	}
+	if got := d.Get(attrNameservers).([]interface{}); len(got) != 1 {
+		t.Errorf("unexpected nameservers: %v", got)
+	}
+	if got := d.Get(attrRecord).(*schema.Set); got.Len() != 1 {
+		t.Errorf("unexpected records: %v", got.List())
+	}
+}
+
+func TestResourceDomainRecordReadPreservesEquivalentData(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.CAAType, Name: api.Ptr, Data: "0 issue letsencrypt.org", TTL: api.DefaultTTL},
+	)
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain: testDomain,
+		attrRecord: []interface{}{
+			map[string]interface{}{recName: api.Ptr, recType: api.CAAType, recData: `0 issue "letsencrypt.org"`},
+		},
+	})
+
+	if diags := resourceDomainRecordRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	records := d.Get(attrRecord).(*schema.Set).List()
+	if len(records) != 1 {
+		t.Fatalf("unexpected records: %v", records)
+	}
+	if got := records[0].(map[string]interface{})[recData]; got != `0 issue "letsencrypt.org"` {
+		t.Errorf("expected the configured CAA data to be preserved, got %q", got)
+	}
+}
+
+func TestResourceDomainRecordUpdateDetectsRemoteChanges(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.AType, Name: "www", Data: "10.0.0.1", TTL: api.DefaultTTL},
+	)
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain: testDomain,
+		attrRecord: []interface{}{
+			map[string]interface{}{recName: "www", recType: api.AType, recData: "10.0.0.1"},
+		},
+	})
+	if diags := resourceDomainRecordRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	changed, _ := api.NewDomainRecord("www", api.AType, "10.0.0.9", api.DefaultTTL)
+	if err := client.SetDomainRecords("", testDomain, api.AType, "www", []*api.DomainRecord{changed}); err != nil {
+		t.Fatal(err)
+	}
+
+	d = resourceDomainRecord().Data(d.State())
+	diags := resourceDomainRecordUpdate(context.Background(), d, client)
+	if !diags.HasError() {
+		t.Fatal("expected the remote change to be detected")
+	}
+	for _, want := range []string{"changed outside of Terraform", "- A www 10.0.0.1", "+ A www 10.0.0.9"} {
+		if !strings.Contains(diags[0].Summary, want) {
+			t.Errorf("expected %q in %q", want, diags[0].Summary)
+		}
+	}
+
+	if err := d.Set(attrForce, true); err != nil {
+		t.Fatal(err)
+	}
+	if diags := resourceDomainRecordUpdate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("expected force to overwrite the remote change: %v", diags)
+	}
+	records, _ := client.GetDomainRecords("", testDomain)
+	if len(records) != 1 || records[0].Data != "10.0.0.1" {
+		t.Errorf("expected the configured record to be applied, got %v", records)
+	}
+}
+
+func TestResourceDomainRecordRestore(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.CNameType, Name: "blog", Data: "@", TTL: api.DefaultTTL},
+	)
+	d := schema.TestResourceDataRaw(t, resourceDomainRecord().Schema, map[string]interface{}{
+		attrDomain: testDomain,
+	})
+
+	if diags := resourceDomainRecordRestore(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	records, _ := client.GetDomainRecords("", testDomain)
+	if len(records) != len(defaultRecords) {
+		t.Errorf("expected default records to be restored, got %d records", len(records))
+	}
+}
+
+const testAccResourceName = "godaddy_domain_record.test"
+
+// newTestAccServer starts a fake GoDaddy API with a zone resembling a newly
+// registered domain
+func newTestAccServer(t *testing.T) *godaddytest.Server {
+	server := godaddytest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddDomain(testDomain,
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns2.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.AType, Name: api.Ptr, Data: "34.102.136.180", TTL: 600},
+		&api.DomainRecord{Type: api.CNameType, Name: "www", Data: "@", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.CNameType, Name: "_domainconnect", Data: "_domainconnect.gd.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.TXTType, Name: api.Ptr, Data: "stale", TTL: api.DefaultTTL},
+	)
+	return server
+}
+
+func TestAccDomainRecord_basic(t *testing.T) {
+	server := newTestAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain    = "example.com"
+  addresses = ["192.168.1.2"]
+
+  record {
+    name = "www"
+    type = "CNAME"
+    data = "example.github.io"
+  }
+
+  record {
+    name     = "@"
+    type     = "MX"
+    data     = "mail.example.com"
+    ttl      = 600
+    priority = 10
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "id", "1000"),
+					resource.TestCheckResourceAttr(testAccResourceName, "addresses.#", "1"),
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "2"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.AType:     1,
+						api.CNameType: 1,
+						api.MXType:    1,
+						api.NSType:    2,
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain    = "example.com"
+  addresses = ["192.168.1.2", "192.168.1.3"]
+
+  record {
+    name = "www"
+    type = "CNAME"
+    data = "example.github.io"
+  }
+
+  record {
+    name = "@"
+    type = "TXT"
+    data = "v=spf1 include:_spf.google.com ~all"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "addresses.#", "2"),
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "2"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.AType:     2,
+						api.CNameType: 1,
+						api.TXTType:   1,
+						api.NSType:    2,
+					}),
+				),
+			},
+			{
+				ResourceName:            testAccResourceName,
+				ImportState:             true,
+				ImportStateId:           testDomain,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{attrNameservers, attrSnapshot, attrRemoteHash},
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_authoritative(t *testing.T) {
+	server := newTestAccServer(t)
+	dns := newTestAccDNSServer(t, "ns6.domains.com", "ns7.domains.com")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				// NS records are left untouched unless nameservers are managed,
+				// while every other record type is replaced
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "@"
+    type = "TXT"
+    data = "managed"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "nameservers.#", "0"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.NSType:  2,
+						api.TXTType: 1,
+					}),
+				),
+			},
+			{
+				Config: testAccResolverConfig(server, dns) + `
+resource "godaddy_domain_record" "test" {
+  domain                  = "example.com"
+  nameservers             = ["ns7.domains.com", "ns6.domains.com"]
+  allow_nameserver_change = true
+
+  record {
+    name = "@"
+    type = "TXT"
+    data = "managed"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "nameservers.#", "2"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.NSType:  2,
+						api.TXTType: 1,
+					}),
+					testAccCheckRemoteRecord(server, api.NSType, api.Ptr, "ns7.domains.com"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_defaultRecordBuckets(t *testing.T) {
+	server := newTestAccServer(t)
+	dns := newTestAccDNSServer(t, "ns3.domaincontrol.com")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				// apex A records are read back into the attribute that declared
+				// them, regardless of their TTL
+				Config: testAccResolverConfig(server, dns) + `
+resource "godaddy_domain_record" "test" {
+  domain                  = "example.com"
+  addresses               = ["192.168.1.2"]
+  nameservers             = ["ns1.domaincontrol.com", "ns2.domaincontrol.com"]
+  allow_nameserver_change = true
+
+  record {
+    name = "@"
+    type = "A"
+    data = "192.168.1.3"
+    ttl  = 600
+  }
+
+  record {
+    name = "@"
+    type = "NS"
+    data = "ns3.domaincontrol.com"
+    ttl  = 600
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "addresses.#", "1"),
+					resource.TestCheckResourceAttr(testAccResourceName, "addresses.0", "192.168.1.2"),
+					resource.TestCheckResourceAttr(testAccResourceName, "nameservers.#", "2"),
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "2"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.AType:  2,
+						api.NSType: 3,
+					}),
+				),
+			},
+			{
+				// apex records declared in the record set stay there, even
+				// when they use the default TTL
+				Config: testAccResolverConfig(server, dns) + `
+resource "godaddy_domain_record" "test" {
+  domain                  = "example.com"
+  addresses               = ["192.168.1.2"]
+  nameservers             = ["ns1.domaincontrol.com", "ns2.domaincontrol.com"]
+  allow_nameserver_change = true
+
+  record {
+    name = "@"
+    type = "A"
+    data = "192.168.1.3"
+  }
+
+  record {
+    name = "@"
+    type = "NS"
+    data = "ns3.domaincontrol.com"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "addresses.#", "1"),
+					resource.TestCheckResourceAttr(testAccResourceName, "addresses.0", "192.168.1.2"),
+					resource.TestCheckResourceAttr(testAccResourceName, "nameservers.#", "2"),
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "2"),
+					resource.TestCheckResourceAttr(testAccResourceName, "ownership.%", "5"),
+					resource.TestCheckResourceAttr(testAccResourceName, "ownership.A 192.168.1.2", api.OwnerAddresses),
+					resource.TestCheckResourceAttr(testAccResourceName, "ownership.A 192.168.1.3", api.OwnerRecord),
+					resource.TestCheckResourceAttr(testAccResourceName, "ownership.NS ns3.domaincontrol.com", api.OwnerRecord),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.AType:  2,
+						api.NSType: 3,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_srv(t *testing.T) {
+	server := newTestAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name     = "@"
+    type     = "SRV"
+    data     = "ldap.example.com"
+    service  = "_ldap"
+    protocol = "_tcp"
+    port     = 389
+    priority = 10
+    weight   = 5
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "1"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.NSType:  2,
+						api.SRVType: 1,
+					}),
+					func(*terraform.State) error {
+						for _, rec := range server.Records(testDomain) {
+							if rec.Type != api.SRVType {
+								continue
+							}
+							if rec.Service != "_ldap" || rec.Protocol != "_tcp" || rec.Port == nil || *rec.Port != 389 || rec.Priority != 10 || rec.Weight != 5 {
+								return fmt.Errorf("unexpected SRV record: %+v", rec)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      testAccResourceName,
+				ImportState:       true,
+				ImportStateId:     testDomain,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					attrNameservers,
+					attrSnapshot,
+					attrRemoteHash,
+				},
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_caa(t *testing.T) {
+	server := newTestAccServer(t)
+	config := testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "@"
+    type = "CAA"
+    data = "0 issue \"letsencrypt.org\""
+  }
+
+  record {
+    name = "@"
+    type = "CAA"
+    data = "0 iodef \"mailto:security@example.com\""
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "2"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.NSType:  2,
+						api.CAAType: 2,
+					}),
+					testAccCheckRemoteRecord(server, api.CAAType, api.Ptr, `0 issue "letsencrypt.org"`),
+				),
+			},
+			{
+				// CAA records changed outside of Terraform are detected as drift
+				PreConfig: func() {
+					server.SetRecords(testDomain,
+						&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+						&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns2.domaincontrol.com", TTL: api.DefaultTTL},
+						&api.DomainRecord{Type: api.CAAType, Name: api.Ptr, Data: `0 issue "digicert.com"`, TTL: api.DefaultTTL},
+					)
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check:  testAccCheckRemoteRecord(server, api.CAAType, api.Ptr, `0 issue "letsencrypt.org"`),
+			},
+			{
+				ResourceName:      testAccResourceName,
+				ImportState:       true,
+				ImportStateId:     testDomain,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					attrNameservers,
+					attrSnapshot,
+					attrRemoteHash,
+				},
+			},
+			{
+				// removing every CAA record deletes them by name
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+}
+`,
+				Check: testAccCheckRemoteRecords(server, map[string]int{
+					api.NSType: 2,
+				}),
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_soa(t *testing.T) {
+	server := newTestAccServer(t)
+	server.SetRecords(testDomain, append(server.Records(testDomain), &api.DomainRecord{
+		Type: api.SOAType,
+		Name: api.Ptr,
+		Data: "ns51.domaincontrol.com. dns.jomax.net. 2023071801 28800 7200 604800 600",
+		TTL:  api.DefaultTTL,
+	})...)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "@"
+    type = "TXT"
+    data = "managed"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "1"),
+					resource.TestCheckResourceAttr(testAccResourceName, "soa.0.mname", "ns51.domaincontrol.com."),
+					resource.TestCheckResourceAttr(testAccResourceName, "soa.0.serial", "2023071801"),
+					resource.TestCheckResourceAttr(testAccResourceName, "soa.0.refresh", "28800"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "@"
+    type = "TXT"
+    data = "managed"
+  }
+
+  soa {
+    refresh = 3600
+    minimum = 1800
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "soa.0.refresh", "3600"),
+					resource.TestCheckResourceAttr(testAccResourceName, "soa.0.retry", "7200"),
+					resource.TestCheckResourceAttr(testAccResourceName, "soa.0.minimum", "1800"),
+					testAccCheckRemoteRecord(server, api.SOAType, api.Ptr, "ns51.domaincontrol.com. dns.jomax.net. 2023071801 3600 7200 604800 1800"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_longTXT(t *testing.T) {
+	server := newTestAccServer(t)
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 12)
+	config := testAccProviderConfig(server) + fmt.Sprintf(`
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "google._domainkey"
+    type = "TXT"
+    data = %q
+  }
+
+  record {
+    name = "@"
+    type = "TXT"
+    data = "v=spf1 include:_spf.google.com ~all"
+  }
+}
+`, dkim)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "2"),
+					func(*terraform.State) error {
+						for _, rec := range server.Records(testDomain) {
+							if rec.Name != "google._domainkey" {
+								continue
+							}
+							if chunks := api.ParseTXT(rec.Data); len(chunks) != 2 || len(chunks[0]) != api.MaxTXTStringLen {
+								return fmt.Errorf("expected the DKIM key to be sent as 2 strings: %s", rec.Data)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// GoDaddy returning quoted data is not reported as drift
+				PreConfig: func() {
+					records := server.Records(testDomain)
+					for _, rec := range records {
+						if rec.Type == api.TXTType && rec.Name == api.Ptr {
+							rec.Data = strconv.Quote(rec.Data)
+						}
+					}
+					server.SetRecords(testDomain, records...)
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      testAccResourceName,
+				ImportState:       true,
+				ImportStateId:     testDomain,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					attrNameservers,
+					attrSnapshot,
+					attrRemoteHash,
+				},
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_names(t *testing.T) {
+	server := newTestAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "www.example.com."
+    type = "CNAME"
+    data = "@"
+  }
+
+  record {
+    name = "Mail.Example.com"
+    type = "A"
+    data = "192.168.1.3"
+  }
+
+  record {
+    name = ""
+    type = "TXT"
+    data = "apex"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "3"),
+					testAccCheckRemoteRecord(server, api.CNameType, "www", "@"),
+					testAccCheckRemoteRecord(server, api.AType, "mail", "192.168.1.3"),
+					testAccCheckRemoteRecord(server, api.TXTType, api.Ptr, "apex"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "www.example.net."
+    type = "CNAME"
+    data = "@"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`outside of domain example.com`),
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_idn(t *testing.T) {
+	server := newTestAccServer(t)
+	server.AddDomain("xn--bcher-kva.example")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "bücher.example"
+
+  record {
+    name = "www"
+    type = "CNAME"
+    data = "shop.bücher.example"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceName, "domain_unicode", "bücher.example"),
+					resource.TestCheckResourceAttr(testAccResourceName, "record.#", "1"),
+					func(*terraform.State) error {
+						records := server.Records("xn--bcher-kva.example")
+						if len(records) != 1 || records[0].Data != "shop.xn--bcher-kva.example" {
+							return fmt.Errorf("expected an A-label CNAME target, got %+v", records)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_invalidData(t *testing.T) {
+	server := newTestAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain    = "example.com"
+  addresses = ["not-an-ip"]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`A data must be an IPv4 address`),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name     = "@"
+    type     = "MX"
+    data     = "https://mail.example.com"
+    priority = 10
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`invalid MX record "@"`),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "@"
+    type = "SOA"
+    data = "ns51.domaincontrol.com. dns.jomax.net. 2023071801 28800 7200 604800 600"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`SOA records are managed with the soa block`),
+			},
+		},
+	})
+
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("expected invalid data to be rejected before any API requests, got %d", n)
+	}
+}
+
+func TestAccDomainRecord_lint(t *testing.T) {
+	server := newTestAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "www"
+    type = "CNAME"
+    data = "@"
+  }
+
+  record {
+    name = "www"
+    type = "TXT"
+    data = "conflict"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`a CNAME may not coexist with other records \(TXT\)`),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_domain_record" "test" {
+  domain = "example.com"
+
+  record {
+    name = "@"
+    type = "CNAME"
+    data = "example.net"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`a CNAME is not permitted at the apex`),
+			},
+		},
+	})
+
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("expected inconsistent records to be rejected before any API requests, got %d", n)
+	}
+}
+
+func TestAccDomainRecord_onDestroy(t *testing.T) {
+	var criteria = []struct {
+		Name     string
+		Provider string
+		Mode     string
+		Check    func(*godaddytest.Server) resource.TestCheckFunc
+	}{
+		{
+			Name: "Given retain",
+			Mode: onDestroyRetain,
+			Check: func(server *godaddytest.Server) resource.TestCheckFunc {
+				return resource.ComposeTestCheckFunc(
+					testAccCheckRemoteRecord(server, api.AType, "api", "10.0.0.1"),
+					testAccCheckRemoteRecord(server, api.TXTType, api.Ptr, "managed"),
+				)
+			},
+		},
+		{
+			Name: "Given remove_managed",
+			Mode: onDestroyRemoveManaged,
+			Check: func(server *godaddytest.Server) resource.TestCheckFunc {
+				return resource.ComposeTestCheckFunc(
+					testAccCheckRemoteRecords(server, map[string]int{api.NSType: 2}),
+				)
+			},
+		},
+		{
+			Name: "Given restore_snapshot",
+			Mode: onDestroyRestoreSnapshot,
+			Check: func(server *godaddytest.Server) resource.TestCheckFunc {
+				return resource.ComposeTestCheckFunc(
+					testAccCheckRemoteRecords(server, map[string]int{api.NSType: 2, api.CNameType: 2, api.AType: 1, api.TXTType: 1}),
+					testAccCheckRemoteRecord(server, api.AType, api.Ptr, "34.102.136.180"),
+					testAccCheckRemoteRecord(server, api.TXTType, api.Ptr, "stale"),
+					testAccCheckRemoteRecord(server, api.CNameType, "www", api.Ptr),
+				)
+			},
+		},
+		{
+			Name: "Given restore_defaults with a provider default record set",
+			Provider: `
+  default_record {
+    name = "www"
+    type = "CNAME"
+    data = "example.net"
+  }
+`,
+			Mode: onDestroyRestoreDefaults,
+			Check: func(server *godaddytest.Server) resource.TestCheckFunc {
+				return resource.ComposeTestCheckFunc(
+					testAccCheckRemoteRecords(server, map[string]int{api.NSType: 2, api.CNameType: 1}),
+					testAccCheckRemoteRecord(server, api.CNameType, "www", "example.net"),
+				)
+			},
+		},
+	}
+	for _, test := range criteria {
+		t.Run(test.Name, func(t *testing.T) {
+			server := newTestAccServer(t)
+			provider := strings.Replace(testAccProviderConfig(server), "}\n", test.Provider+"}\n", 1)
+
+			resource.Test(t, resource.TestCase{
+				ProviderFactories: testAccProviderFactories,
+				CheckDestroy:      test.Check(server),
+				Steps: []resource.TestStep{
+					{
+						Config: provider + fmt.Sprintf(`
+resource "godaddy_domain_record" "test" {
+  domain     = "example.com"
+  on_destroy = %q
+
+  record {
+    name = "api"
+    type = "A"
+    data = "10.0.0.1"
+  }
+
+  record {
+    name = "@"
+    type = "TXT"
+    data = "managed"
+  }
+}
+`, test.Mode),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr(testAccResourceName, "snapshot.#", "6"),
+							testAccCheckRemoteRecords(server, map[string]int{api.NSType: 2, api.AType: 1, api.TXTType: 1}),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccDomainRecord_safeguards(t *testing.T) {
+	server := newTestAccServer(t)
+	provider := strings.Replace(testAccProviderConfig(server), "}\n", "  max_records_deleted = 3\n}\n", 1)
+
+	config := func(override bool, mx bool) string {
+		var records string
+		if mx {
+			records = `
+  record {
+    name     = "@"
+    type     = "MX"
+    data     = "mail.example.net"
+    priority = 10
+  }
+`
+		}
+		return provider + fmt.Sprintf(`
+resource "godaddy_domain_record" "test" {
+  domain              = "example.com"
+  override_safeguards = %t
+  protected_records   = ["MX @"]
+
+  record {
+    name = "api"
+    type = "A"
+    data = "10.0.0.1"
+  }
+%s}
+`, override, records)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config:      config(false, false),
+				ExpectError: regexp.MustCompile(`4 records would be deleted, exceeding the limit of 3`),
+			},
+			{
+				Config: config(true, false),
+				Check:  testAccCheckRemoteRecords(server, map[string]int{api.NSType: 2, api.AType: 1}),
+			},
+			{
+				Config: config(false, true),
+				Check:  testAccCheckRemoteRecords(server, map[string]int{api.NSType: 2, api.AType: 1, api.MXType: 1}),
+			},
+			{
+				Config:      config(false, false),
+				ExpectError: regexp.MustCompile(`protected MX records at @ would be removed`),
+			},
+			{
+				// restoring the default records would remove the protected MX
+				Config:      config(false, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`protected MX records at @ would be removed`),
+			},
+			{
+				Config: config(true, false),
+				Check:  testAccCheckRemoteRecords(server, map[string]int{api.NSType: 2, api.AType: 1}),
+			},
+		},
+	})
+}
+
+func TestAccDomainRecord_nameserverChange(t *testing.T) {
+	server := newTestAccServer(t)
+	dns := newTestAccDNSServer(t, "ns7.domains.com")
+
+	config := func(allow bool, nameservers ...string) string {
+		return testAccResolverConfig(server, dns) + fmt.Sprintf(`
+resource "godaddy_domain_record" "test" {
+  domain                  = "example.com"
+  nameservers             = ["%s"]
+  allow_nameserver_change = %t
+}
+`, strings.Join(nameservers, `", "`), allow)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				// the confirmation is required when planning
+				Config:      config(false, "ns7.domains.com"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`set allow_nameserver_change = true to confirm the change`),
+			},
+			{
+				Config:      config(true, "ns7.domains.com", "ns6.domains.com"),
+				ExpectError: regexp.MustCompile(`ns6.domains.com does not resolve`),
+			},
+			{
+				Config: config(true, "ns7.domains.com"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRemoteRecords(server, map[string]int{api.NSType: 1}),
+					testAccCheckRemoteRecord(server, api.NSType, api.Ptr, "ns7.domains.com"),
+				),
+			},
+			{
+				// unchanged nameservers don't require confirmation
+				Config: config(false, "ns7.domains.com"),
+				Check:  testAccCheckRemoteRecords(server, map[string]int{api.NSType: 1}),
+			},
+		},
+	})
+}
+
+// testAccCheckRemoteRecords verifies the number of records of each type
+// stored by the fake server
+func testAccCheckRemoteRecords(server *godaddytest.Server, expected map[string]int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		actual := make(map[string]int)
+		for _, rec := range server.Records(testDomain) {
+			actual[rec.Type]++
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("expected remote records %v, got %v", expected, actual)
+		}
+		return nil
+	}
+}
+
+func testAccCheckRemoteRecord(server *godaddytest.Server, t, name, data string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, rec := range server.Records(testDomain) {
+			if rec.Type == t && rec.Name == name && rec.Data == data {
+				return nil
+			}
+		}
+		return fmt.Errorf("remote %s record %s => %s not found", t, name, data)
+	}
+}
+
+// testAccCheckDomainRecordDestroy verifies that the default records were
+// restored once the resource was destroyed
+func testAccCheckDomainRecordDestroy(server *godaddytest.Server) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, rec := range defaultRecords {
+			if err := testAccCheckRemoteRecord(server, rec.Type, rec.Name, rec.Data)(nil); err != nil {
+				return err
+			}
+		}
+		for _, rec := range server.Records(testDomain) {
+			if rec.Type != api.NSType && rec.Type != api.CNameType {
+				return fmt.Errorf("unexpected record remaining after destroy: %+v", rec)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,313 @@
+package godaddy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+	"github.com/n3integration/terraform-provider-godaddy/api/zonefile"
+)
+
+const (
+	attrContent = "content"
+	attrRecords = "records"
+)
+
+func resourceZoneFile() *schema.Resource {
+	r := &schema.Resource{
+		CreateContext: resourceZoneFileCreate,
+		ReadContext:   resourceZoneFileRead,
+		UpdateContext: resourceZoneFileUpdate,
+		DeleteContext: resourceZoneFileRestore,
+		CustomizeDiff: resourceZoneFileCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			// Required
+			attrDomain: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			attrContent: {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentZoneFile,
+				Description:      "Zone file content in RFC 1035 (BIND) format, relative to the domain.",
+			},
+			// Optional
+			attrCustomer: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			attrOnDestroy:             onDestroySchema(),
+			attrForce:                 forceSchema(),
+			attrOverrideSafeguards:    overrideSafeguardsSchema(),
+			attrAllowNameserverChange: allowNameserverChangeSchema(),
+			// Computed
+			attrUnicode:    unicodeSchema(),
+			attrSnapshot:   snapshotSchema(),
+			attrRemoteHash: remoteHashSchema(),
+			attrRecords: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Managed records, one zone file entry per record.",
+			},
+		},
+	}
+
+	for k, v := range safeguardSchemas("uses the provider setting") {
+		r.Schema[k] = v
+	}
+	return r
+}
+
+func resourceZoneFileRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(api.DNSClient)
+	customer := d.Get(attrCustomer).(string)
+	domain := d.Get(attrDomain).(string)
+
+	// Importer support
+	imported := domain == ""
+	if imported {
+		domain = d.Id()
+	}
+
+	logger.Debug("fetching zone file records", "domain", domain)
+	remote, err := client.GetDomainRecords(customer, domain)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("couldn't find domain record (%s): %s", domain, err.Error()))
+	}
+
+	desired := remote
+	if imported {
+		lines, err := zoneFileLines(remote)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set(attrSnapshot, lines)
+		d.Set(attrOnDestroy, onDestroyRestoreDefaults)
+		d.Set(attrForce, false)
+		d.Set(attrOverrideSafeguards, false)
+		d.Set(attrAllowNameserverChange, false)
+	} else {
+		if desired, err = parseZoneFile(domain, d.Get(attrContent).(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	managed := api.ReplacedRecords(remote, desired)
+	lines, err := zoneFileLines(managed)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrRecords, lines); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrRemoteHash, api.HashRecords(domain, managed)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if imported {
+		content, err := zonefile.Marshal(domain, managed)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set(attrDomain, domain)
+		d.Set(attrContent, string(content))
+	}
+
+	return diag.FromErr(populateZoneFileDomainInfo(client, customer, domain, d))
+}
+
+func resourceZoneFileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(api.DNSClient)
+	customer := d.Get(attrCustomer).(string)
+	domain := d.Get(attrDomain).(string)
+	defer lockDomain(customer, domain)()
+
+	remote, err := captureSnapshot(client, customer, domain, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info("creating zone file records", "domain", domain)
+	if diags := applyZoneFile(ctx, d, meta, remote); diags.HasError() {
+		return diags
+	}
+	return resourceZoneFileRead(ctx, d, meta)
+}
+
+func resourceZoneFileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	defer lockDomain(d.Get(attrCustomer).(string), d.Get(attrDomain).(string))()
+	client := meta.(api.DNSClient)
+	remote, err := checkZoneFileUnchanged(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info("updating zone file records", "domain", d.Get(attrDomain))
+	if diags := applyZoneFile(ctx, d, meta, remote); diags.HasError() {
+		return diags
+	}
+	return resourceZoneFileRead(ctx, d, meta)
+}
+
+func resourceZoneFileRestore(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	customer := d.Get(attrCustomer).(string)
+	domain := d.Get(attrDomain).(string)
+	defer lockDomain(customer, domain)()
+
+	records, err := parseZoneFile(domain, d.Get(attrContent).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return destroyRecords(d, meta, customer, domain, records)
+}
+
+// resourceZoneFileCustomizeDiff renders the desired records at plan time so
+// that each added or removed record is shown in the plan
+func resourceZoneFileCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(attrContent) || !d.NewValueKnown(attrDomain) {
+		return d.SetNewComputed(attrRecords)
+	}
+
+	domain := d.Get(attrDomain).(string)
+	records, err := parseZoneFile(domain, d.Get(attrContent).(string))
+	if err != nil {
+		return err
+	}
+	if d.Id() == "" || d.HasChange(attrContent) {
+		if err := planNameserverChange(d, meta, domain, records); err != nil {
+			return err
+		}
+	}
+
+	// the SOA record is managed by GoDaddy, so only the records that Read
+	// stores are rendered
+	lines, err := zoneFileLines(api.ReplacedRecords(records, records))
+	if err != nil {
+		return err
+	}
+
+	old := d.Get(attrRecords).(*schema.Set)
+	if d.Id() != "" && old.Equal(schema.NewSet(schema.HashString, toInterfaces(lines))) {
+		return nil
+	}
+	return d.SetNew(attrRecords, lines)
+}
+
+// applyZoneFile replaces the remote records with those of the zone file,
+// provided that the changes do not trip the safeguards
+func applyZoneFile(ctx context.Context, d *schema.ResourceData, meta interface{}, remote []*api.DomainRecord) diag.Diagnostics {
+	client := meta.(api.DNSClient)
+	customer := d.Get(attrCustomer).(string)
+	domain := d.Get(attrDomain).(string)
+
+	records, err := parseZoneFile(domain, d.Get(attrContent).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := populateZoneFileDomainInfo(client, customer, domain, d); err != nil {
+		return diag.FromErr(err)
+	}
+	if diags := checkSafeguards(d, meta, domain, remote, records); diags.HasError() {
+		return diags
+	}
+	if err := checkNameserverChange(ctx, d, meta, domain, remote, records); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := client.UpdateDomainRecords(customer, domain, records); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// checkZoneFileUnchanged compares the records managed by the prior zone file
+// content with those recorded by the last read, returning the remote records
+func checkZoneFileUnchanged(client api.DNSClient, d *schema.ResourceData) ([]*api.DomainRecord, error) {
+	customer := d.Get(attrCustomer).(string)
+	domain := d.Get(attrDomain).(string)
+
+	oldContent, _ := d.GetChange(attrContent)
+	desired, err := parseZoneFile(domain, oldContent.(string))
+	if err != nil {
+		return nil, err
+	}
+	remote, err := client.GetDomainRecords(customer, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	oldRecords, _ := d.GetChange(attrRecords)
+	lines := make([]string, 0, oldRecords.(*schema.Set).Len())
+	for _, line := range oldRecords.(*schema.Set).List() {
+		lines = append(lines, line.(string))
+	}
+	previous, err := parseZoneFile(domain, strings.Join(lines, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	return remote, checkRemoteUnchanged(d, domain, previous, api.ReplacedRecords(remote, desired))
+}
+
+func populateZoneFileDomainInfo(client api.DNSClient, customer, domain string, d *schema.ResourceData) error {
+	return populateDomainInfo(client, &domainRecordResource{Customer: customer, Domain: domain}, d)
+}
+
+func parseZoneFile(domain, content string) ([]*api.DomainRecord, error) {
+	records, err := zonefile.Parse(strings.NewReader(content), domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone file content: %s", err)
+	}
+	return records, nil
+}
+
+// zoneFileLines renders each record as a sorted zone file entry
+func zoneFileLines(records []*api.DomainRecord) ([]string, error) {
+	lines := make([]string, len(records))
+	for i, rec := range records {
+		line, err := zonefile.FormatRecord(rec)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = line
+	}
+	sort.Strings(lines)
+	return lines, nil
+}
+
+// suppressEquivalentZoneFile ignores formatting and comment changes that do
+// not alter the records described by the zone file
+func suppressEquivalentZoneFile(_, old, new string, d *schema.ResourceData) bool {
+	domain := d.Get(attrDomain).(string)
+	oldRecords, err := parseZoneFile(domain, old)
+	if err != nil {
+		return false
+	}
+	newRecords, err := parseZoneFile(domain, new)
+	if err != nil {
+		return false
+	}
+
+	oldLines, _ := zoneFileLines(oldRecords)
+	newLines, _ := zoneFileLines(newRecords)
+	return strings.Join(oldLines, "\n") == strings.Join(newLines, "\n")
+}
+
+func toInterfaces(list []string) []interface{} {
+	result := make([]interface{}, len(list))
+	for i, v := range list {
+		result[i] = v
+	}
+	return result
+}
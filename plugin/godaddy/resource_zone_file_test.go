@@ -0,0 +1,179 @@
+package godaddy
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const testZoneFile = `$TTL 3600
+@	IN	A	192.168.1.2
+www	IN	CNAME	@
+@	IN	MX	10 mail.example.com.
+@	IN	TXT	"v=spf1 include:_spf.google.com ~all"
+`
+
+func TestResourceZoneFileCreate(t *testing.T) {
+	client := newTestMemoryClient(
+		&api.DomainRecord{Type: api.NSType, Name: api.Ptr, Data: "ns1.domaincontrol.com", TTL: api.DefaultTTL},
+		&api.DomainRecord{Type: api.TXTType, Name: "stale", Data: "remove me", TTL: api.DefaultTTL},
+	)
+	d := schema.TestResourceDataRaw(t, resourceZoneFile().Schema, map[string]interface{}{
+		attrDomain:  testDomain,
+		attrContent: testZoneFile,
+	})
+
+	if diags := resourceZoneFileCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "42" {
+		t.Errorf("expected id to be the domain id, got %q", d.Id())
+	}
+	if got := d.Get(attrRecords).(*schema.Set).Len(); got != 4 {
+		t.Errorf("expected 4 managed records, got %d", got)
+	}
+
+	records, _ := client.GetDomainRecords("", testDomain)
+	if len(records) != 5 {
+		t.Errorf("expected the NS record to be retained alongside 4 records, got %d", len(records))
+	}
+}
+
+func TestResourceZoneFileInvalidContent(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceZoneFile().Schema, map[string]interface{}{
+		attrDomain:  testDomain,
+		attrContent: "@ IN PTR host.example.com.",
+	})
+
+	if diags := resourceZoneFileCreate(context.Background(), d, newTestMemoryClient()); !diags.HasError() {
+		t.Fatal("expected an error for an unsupported record type")
+	}
+}
+
+func TestSuppressEquivalentZoneFile(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceZoneFile().Schema, map[string]interface{}{
+		attrDomain: testDomain,
+	})
+
+	reformatted := "; comment\n" + strings.ReplaceAll(testZoneFile, "\t", "  ")
+	if !suppressEquivalentZoneFile(attrContent, testZoneFile, reformatted, d) {
+		t.Error("expected formatting changes to be suppressed")
+	}
+	if suppressEquivalentZoneFile(attrContent, testZoneFile, testZoneFile+"api IN A 192.168.1.3\n", d) {
+		t.Error("expected record changes to be reported")
+	}
+}
+
+func TestAccZoneFile_basic(t *testing.T) {
+	server := newTestAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_zone_file" "test" {
+  domain  = "example.com"
+  content = <<EOT
+` + testZoneFile + `EOT
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("godaddy_zone_file.test", "records.#", "4"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.AType:     1,
+						api.CNameType: 1,
+						api.MXType:    1,
+						api.TXTType:   1,
+						api.NSType:    2,
+					}),
+				),
+			},
+			{
+				// out-of-band changes are detected and reverted
+				PreConfig: func() {
+					records := server.Records(testDomain)
+					server.SetRecords(testDomain, append(records,
+						&api.DomainRecord{Type: api.AType, Name: "rogue", Data: "10.0.0.1", TTL: 600})...)
+				},
+				Config: testAccProviderConfig(server) + `
+resource "godaddy_zone_file" "test" {
+  domain  = "example.com"
+  content = <<EOT
+` + testZoneFile + `EOT
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("godaddy_zone_file.test", "records.#", "4"),
+					testAccCheckRemoteRecords(server, map[string]int{
+						api.AType:     1,
+						api.CNameType: 1,
+						api.MXType:    1,
+						api.TXTType:   1,
+						api.NSType:    2,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccZoneFile_soa(t *testing.T) {
+	server := newTestAccServer(t)
+
+	// zone files exported by godaddy-dns begin with the SOA record, which
+	// is managed by GoDaddy and excluded from the managed records
+	config := testAccProviderConfig(server) + `
+resource "godaddy_zone_file" "test" {
+  domain  = "example.com"
+  content = <<EOT
+@	3600	IN	SOA	ns1.domaincontrol.com. dns.jomax.net. 2021010101 28800 7200 604800 600
+` + testZoneFile + `EOT
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDomainRecordDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("godaddy_zone_file.test", "records.#", "4"),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccZoneFile_nameserverChange(t *testing.T) {
+	server := newTestAccServer(t)
+
+	config := testAccProviderConfig(server) + `
+resource "godaddy_zone_file" "test" {
+  domain  = "example.com"
+  content = <<EOT
+@	IN	NS	ns7.domains.com.
+` + testZoneFile + `EOT
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`changing the nameservers of example.com from \[.+\] to \[ns7.domains.com\]`),
+			},
+		},
+	})
+}
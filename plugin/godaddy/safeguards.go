@@ -0,0 +1,134 @@
+package godaddy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+const (
+	attrMaxRecordsDeleted  = "max_records_deleted"
+	attrMaxPercentChanged  = "max_percent_changed"
+	attrProtectedRecords   = "protected_records"
+	attrOverrideSafeguards = "override_safeguards"
+)
+
+// safeguardSchemas returns the safeguard settings shared by the provider and
+// the resources. A resource's limits take precedence over the provider's,
+// while its protected records are added to the provider's.
+func safeguardSchemas(scope string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		attrMaxRecordsDeleted: {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			Description:      fmt.Sprintf("Refuse to apply changes that delete more than this many records (0 %s).", scope),
+		},
+		attrMaxPercentChanged: {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 100)),
+			Description:      fmt.Sprintf("Refuse to apply changes that modify or delete more than this percentage of the existing records (0 %s).", scope),
+		},
+		attrProtectedRecords: {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type:             schema.TypeString,
+				ValidateDiagFunc: validateString(api.ValidateProtectedPattern),
+			},
+			Description: "Records that may not be removed, as \"TYPE\" or \"TYPE NAME\" patterns (e.g. \"MX\" or \"NS @\"). The name may contain glob wildcards.",
+		},
+	}
+}
+
+func overrideSafeguardsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Apply changes even if they trip the provider or resource safeguards.",
+	}
+}
+
+// expandSafeguards reads the safeguard settings of the provider or a resource
+func expandSafeguards(d *schema.ResourceData) api.Safeguards {
+	patterns := d.Get(attrProtectedRecords).([]interface{})
+	protected := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		protected = append(protected, p.(string))
+	}
+
+	return api.Safeguards{
+		MaxRecordsDeleted: d.Get(attrMaxRecordsDeleted).(int),
+		MaxPercentChanged: d.Get(attrMaxPercentChanged).(int),
+		ProtectedRecords:  protected,
+	}
+}
+
+// resourceSafeguards merges the safeguards of a resource with those of the
+// provider
+func resourceSafeguards(d *schema.ResourceData, meta interface{}) api.Safeguards {
+	var safeguards api.Safeguards
+	if m, ok := meta.(*providerMeta); ok {
+		safeguards = m.Safeguards
+	}
+
+	local := expandSafeguards(d)
+	if local.MaxRecordsDeleted > 0 {
+		safeguards.MaxRecordsDeleted = local.MaxRecordsDeleted
+	}
+	if local.MaxPercentChanged > 0 {
+		safeguards.MaxPercentChanged = local.MaxPercentChanged
+	}
+	safeguards.ProtectedRecords = append(append([]string(nil), safeguards.ProtectedRecords...), local.ProtectedRecords...)
+	return safeguards
+}
+
+// checkSafeguards evaluates the safeguards against the changes that
+// replacing the current records with desired would make, before any records
+// are updated
+func checkSafeguards(d *schema.ResourceData, meta interface{}, domain string, current, desired []*api.DomainRecord) diag.Diagnostics {
+	if d.Get(attrOverrideSafeguards).(bool) {
+		logger.Warn("safeguards overridden", "domain", domain)
+		return nil
+	}
+	return safeguardDiagnostics(domain, resourceSafeguards(d, meta).Check(domain, current, desired))
+}
+
+// checkDestroySafeguards evaluates the safeguards against the records that
+// remain once the on_destroy behavior has been applied
+func checkDestroySafeguards(d *schema.ResourceData, meta interface{}, domain string, before, after []*api.DomainRecord) diag.Diagnostics {
+	if d.Get(attrOverrideSafeguards).(bool) {
+		logger.Warn("safeguards overridden", "domain", domain)
+		return nil
+	}
+	return safeguardDiagnostics(domain, resourceSafeguards(d, meta).CheckChange(domain, before, after))
+}
+
+// safeguardDiagnostics details the violations and refused changes of a
+// tripped safeguard
+func safeguardDiagnostics(domain string, err error) diag.Diagnostics {
+	var tripped *api.SafeguardError
+	if !errors.As(err, &tripped) {
+		return diag.FromErr(err)
+	}
+
+	var detail strings.Builder
+	for _, v := range tripped.Violations {
+		fmt.Fprintf(&detail, "  - %s\n", v)
+	}
+	fmt.Fprintf(&detail, "\nThe following changes were refused:\n\n%s\n\n", tripped.Diff)
+	fmt.Fprintf(&detail, "Review the configuration, or set %s = true to apply these changes anyway.", attrOverrideSafeguards)
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Safeguards prevented changes to %s", domain),
+		Detail:   detail.String(),
+	}}
+}
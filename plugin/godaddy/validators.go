@@ -0,0 +1,152 @@
+package godaddy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/n3integration/terraform-provider-godaddy/api"
+)
+
+// validateRecordType ensures that a record type is supported by GoDaddy and
+// may be managed as a record block
+func validateRecordType(v interface{}, path cty.Path) diag.Diagnostics {
+	switch t := v.(string); {
+	case t == api.SOAType:
+		return validationError(fmt.Errorf("SOA records are managed with the %s block", attrSOA), path)
+	case !api.IsSupportedType(t):
+		return validationError(fmt.Errorf("unsupported record type %q", t), path)
+	}
+	return nil
+}
+
+// validateSOATimer performs bounds checking on an SOA timer
+func validateSOATimer(v interface{}, path cty.Path) diag.Diagnostics {
+	if err := api.ValidateSOATimer(v.(int)); err != nil {
+		return validationError(err, path)
+	}
+	return nil
+}
+
+// validateRecordData returns a validator for the data of a record type
+func validateRecordData(t string) schema.SchemaValidateDiagFunc {
+	return validateString(func(data string) error {
+		return api.ValidateData(t, data)
+	})
+}
+
+// validateString adapts a string check into a validator that is safe to use
+// on list elements, where validation.ToDiagFunc panics
+func validateString(check func(string) error) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		if err := check(v.(string)); err != nil {
+			return validationError(err, path)
+		}
+		return nil
+	}
+}
+
+func validationError(err error, path cty.Path) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       err.Error(),
+		AttributePath: path,
+	}}
+}
+
+// validateRecords checks the type specific data of each configured record at
+// plan time. Validation functions are not supported on set attributes, and
+// the data rules depend upon the record type, so this runs as part of the
+// CustomizeDiff.
+func validateRecords(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown(attrRecord) {
+		return nil
+	}
+
+	domain := d.Get(attrDomain).(string)
+	for _, rec := range d.Get(attrRecord).(*schema.Set).List() {
+		data := rec.(map[string]interface{})
+		t, name := data[recType].(string), data[recName].(string)
+		if !api.IsSupportedType(t) {
+			continue
+		}
+		if d.NewValueKnown(attrDomain) {
+			if _, err := api.NormalizeName(domain, name); err != nil {
+				return fmt.Errorf("invalid %s record %q: %s", t, name, err)
+			}
+		}
+		if err := api.ValidateData(t, data[recData].(string)); err != nil {
+			return fmt.Errorf("invalid %s record %q: %s", t, name, err)
+		}
+	}
+	return nil
+}
+
+// lintRecords checks the configured records for RFC violations that GoDaddy
+// accepts, such as a CNAME alongside other records. Errors fail the plan and
+// warnings are logged, since a CustomizeDiff cannot return them; lintWarnings
+// reports them as diagnostics when the records are applied.
+func lintRecords(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown(attrDomain) || !d.NewValueKnown(attrRecord) ||
+		!d.NewValueKnown(attrAddresses) || !d.NewValueKnown(attrNameservers) {
+		return nil
+	}
+
+	domain := d.Get(attrDomain).(string)
+	issues := api.LintRecords(domain, plannedRecords(d))
+	for _, issue := range issues {
+		if issue.Severity == api.SeverityWarning {
+			logger.Warn(issue.Error(), "domain", domain)
+		}
+	}
+
+	errs := api.LintErrors(issues)
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("inconsistent records for %s:\n  %s", domain, strings.Join(msgs, "\n  "))
+}
+
+// plannedRecords collects the records of the planned configuration,
+// including the apex addresses and nameservers
+func plannedRecords(d *schema.ResourceDiff) []*api.DomainRecord {
+	var records []*api.DomainRecord
+	for _, rec := range d.Get(attrRecord).(*schema.Set).List() {
+		data := rec.(map[string]interface{})
+		records = append(records, &api.DomainRecord{
+			Name: data[recName].(string),
+			Type: data[recType].(string),
+			Data: data[recData].(string),
+		})
+	}
+	for _, data := range d.Get(attrAddresses).([]interface{}) {
+		records = append(records, &api.DomainRecord{Name: api.Ptr, Type: api.AType, Data: data.(string)})
+	}
+	for _, data := range d.Get(attrNameservers).([]interface{}) {
+		records = append(records, &api.DomainRecord{Name: api.Ptr, Type: api.NSType, Data: data.(string)})
+	}
+	return records
+}
+
+// lintWarnings reports the lint warnings for the applied records
+func lintWarnings(domain string, records []*api.DomainRecord) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, issue := range api.LintRecords(domain, records) {
+		if issue.Severity != api.SeverityWarning {
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Questionable record for %s", domain),
+			Detail:   issue.Error(),
+		})
+	}
+	return diags
+}